@@ -21,25 +21,79 @@ type Controller interface {
 	Reset(configuration *model.ComponentConfiguration) (status *model.ComponentStatus, err error)
 }
 
+// ActionController is implemented by controllers which provide methods beyond
+// the standard operations, e.g. for the transitions of custom state machines.
+type ActionController interface {
+	Action(method string, configuration *model.ComponentConfiguration) (status *model.ComponentStatus, err error)
+}
+
+// ProbeController is implemented by controllers which are able to verify
+// whether an instance is ready to serve requests and whether it is still alive.
+type ProbeController interface {
+	Ready(configuration *model.ComponentConfiguration) (ready bool, err error)
+	Alive(configuration *model.ComponentConfiguration) (alive bool, err error)
+}
+
 //------------------------------------------------------------------------------
 
 var controllers map[string]Controller
+var controllersLock sync.RWMutex
 
 var once sync.Once
 
 //------------------------------------------------------------------------------
 
-// GetController retrieves a controller for a specific component type.
-func GetController(componentType string) (Controller, error) {
+// initControllers registers the built-in controllers.
+func initControllers() {
 	// initialise singleton once
 	once.Do(func() {
 		controllers = map[string]Controller{}
 
 		controllers["file"] = file.Controller{}
 	})
+}
+
+//------------------------------------------------------------------------------
+
+// RegisterController registers a controller for a specific component type
+// together with the state machine describing the lifecycle of its instances.
+// The built-in lifecycle applies if no state machine has been defined.
+func RegisterController(componentType string, controller Controller, machine *model.StateMachine) error {
+	initControllers()
+
+	// check parameters
+	if controller == nil {
+		return errors.New("undefined controller")
+	}
+
+	// register state machine
+	if machine != nil {
+		err := model.RegisterStateMachine(componentType, machine)
+		if err != nil {
+			return err
+		}
+	}
+
+	// register controller
+	controllersLock.Lock()
+	controllers[componentType] = controller
+	controllersLock.Unlock()
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// GetController retrieves a controller for a specific component type.
+func GetController(componentType string) (Controller, error) {
+	initControllers()
 
 	// determine controller
-	controller, found := (controllers)[componentType]
+	controllersLock.RLock()
+	controller, found := controllers[componentType]
+	controllersLock.RUnlock()
+
 	if !found {
 		return nil, errors.New("unknown type")
 	}
@@ -49,3 +103,47 @@ func GetController(componentType string) (Controller, error) {
 }
 
 //------------------------------------------------------------------------------
+
+// Execute invokes the controller method which implements a transition unless
+// the context of the configuration has already been cancelled. Controllers are
+// expected to abort the operation once the context is cancelled - the call
+// does not return before the operation has ended, so that no operation of a
+// cancelled task continues in the background.
+func Execute(controller Controller, method string, configuration *model.ComponentConfiguration) (*model.ComponentStatus, error) {
+	// do not start operations which have already been cancelled
+	if err := configuration.GetContext().Err(); err != nil {
+		return nil, err
+	}
+
+	return invoke(controller, method, configuration)
+}
+
+//------------------------------------------------------------------------------
+
+// invoke calls the controller method which implements a transition.
+func invoke(controller Controller, method string, configuration *model.ComponentConfiguration) (*model.ComponentStatus, error) {
+	switch method {
+	case "create":
+		return controller.Create(configuration)
+	case "start":
+		return controller.Start(configuration)
+	case "stop":
+		return controller.Stop(configuration)
+	case "destroy":
+		return controller.Destroy(configuration)
+	case "reset":
+		return controller.Reset(configuration)
+	case "configure":
+		return controller.Configure(configuration)
+	}
+
+	// delegate custom methods
+	if actionController, ok := controller.(ActionController); ok {
+		return actionController.Action(method, configuration)
+	}
+
+	// unknown method
+	return nil, errors.New("unknown method: " + method)
+}
+
+//------------------------------------------------------------------------------
@@ -24,23 +24,19 @@ func (c Controller) Create(configuration *model.ComponentConfiguration) (status
 	if found {
 		parentEndpoint, _ := DecodeEndpoint(parent.Endpoint)
 
-		parentPath = parentPath + parentEndpoint.Path + "/"
+		parentPath = parentEndpoint.Path
 	}
 
-	// create <path> directory if it does not exist yet
+	// create <path> directory (including missing parents) if it does not exist yet
 	path := parentPath + "/" + config.Name
-	if _, err = os.Stat(path); os.IsNotExist(err) {
-		if err = os.Mkdir(path, os.ModePerm); err != nil {
-			return nil, errors.New("unable to create directory")
-		}
+	if err = os.MkdirAll(path, os.ModePerm); err != nil {
+		return nil, errors.New("unable to create directory")
 	}
 
 	// create <path>/.data directory if it does not exist yet
 	dataPath := path + "/.data"
-	if _, err = os.Stat(dataPath); os.IsNotExist(err) {
-		if err = os.Mkdir(dataPath, os.ModePerm); err != nil {
-			return nil, errors.New("unable to create .data directory")
-		}
+	if err = os.MkdirAll(dataPath, os.ModePerm); err != nil {
+		return nil, errors.New("unable to create .data directory")
 	}
 
 	// create <path>/.data/.component file
@@ -62,7 +58,7 @@ func (c Controller) Create(configuration *model.ComponentConfiguration) (status
 		Component: configuration.Component,
 		Instance:  configuration.Instance,
 		Version:   instance.Version,
-		State:     model.InitialState,
+		State:     model.InactiveState,
 		Path:      path,
 		Endpoint: endpointInfo{
 			Path: path,
@@ -95,7 +91,7 @@ func (c Controller) Create(configuration *model.ComponentConfiguration) (status
 	status.ComponentEndpoint = ep
 	status.VersionEndpoint = ep
 	status.InstanceEndpoint = ep
-	status.InstanceState = model.InitialState
+	status.InstanceState = model.InactiveState
 	status.Changed = true
 
 	return status, nil
@@ -25,15 +25,15 @@ func (c Controller) Destroy(configuration *model.ComponentConfiguration) (status
 	if found {
 		parentEndpoint, _ := DecodeEndpoint(parent.Endpoint)
 
-		parentPath = parentPath + parentEndpoint.Path + "/"
+		parentPath = parentEndpoint.Path
 	}
 
 	path := parentPath + "/" + config.Name
 
-	// delete instance file
+	// delete instance file (an instance which has not been created completely has none)
 	instancePath := path + "/.data/" + instance.UUID
 	err = os.Remove(instancePath)
-	if err != nil {
+	if err != nil && !os.IsNotExist(err) {
 		status.ComponentEndpoint = ""
 		status.VersionEndpoint = ""
 		status.InstanceEndpoint = ""
@@ -46,7 +46,7 @@ func (c Controller) Destroy(configuration *model.ComponentConfiguration) (status
 	// delete <path> directory if no other instances exist
 	dataPath := path + "/.data"
 	files, err := ioutil.ReadDir(dataPath)
-	if err != nil {
+	if err != nil && !os.IsNotExist(err) {
 		status.ComponentEndpoint = ""
 		status.VersionEndpoint = ""
 		status.InstanceEndpoint = ""
@@ -24,7 +24,7 @@ func (c Controller) Start(configuration *model.ComponentConfiguration) (status *
 	if found {
 		parentEndpoint, _ := DecodeEndpoint(parent.Endpoint)
 
-		parentPath = parentPath + parentEndpoint.Path + "/"
+		parentPath = parentEndpoint.Path
 	}
 
 	// define paths
@@ -24,7 +24,7 @@ func (c Controller) Status(configuration *model.ComponentConfiguration) (status
 	if found {
 		parentEndpoint, _ := DecodeEndpoint(parent.Endpoint)
 
-		parentPath = parentPath + parentEndpoint.Path + "/"
+		parentPath = parentEndpoint.Path
 	}
 	path := parentPath + "/" + config.Name
 
@@ -65,8 +65,15 @@ func (c Controller) Status(configuration *model.ComponentConfiguration) (status
 	status.ComponentEndpoint = ep
 	status.ComponentEndpoint = ep
 
-	// read instance info
+	// check if instance file exists
 	instancePath := path + "/.data/" + configuration.Instance
+	if _, err = os.Stat(instancePath); os.IsNotExist(err) {
+		status.InstanceState = model.InitialState
+
+		return status, nil
+	}
+
+	// read instance info
 	instanceInfo, err := LoadInstanceInfo(instancePath)
 	if err != nil {
 		status.InstanceState = model.FailureState
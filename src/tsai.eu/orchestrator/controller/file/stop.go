@@ -23,7 +23,7 @@ func (c Controller) Stop(configuration *model.ComponentConfiguration) (status *m
 	if found {
 		parentEndpoint, _ := DecodeEndpoint(parent.Endpoint)
 
-		parentPath = parentPath + parentEndpoint.Path + "/"
+		parentPath = parentEndpoint.Path
 	}
 
 	// define paths
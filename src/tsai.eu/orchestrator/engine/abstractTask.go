@@ -11,10 +11,13 @@ func TerminateTask(task *model.Task) {
 	// get event channel
 	channel := GetEventChannel()
 
-	// check if task is regarded to be executing
-	if task.Status == model.TaskStatusExecuting {
+	// check if task has not finished yet
+	if status := task.GetStatus(); status == model.TaskStatusInitial || status == model.TaskStatusExecuting {
 		// update status
-		task.Status = model.TaskStatusTerminated
+		task.SetStatus(model.TaskStatusTerminated)
+
+		// abort the running operation of the task
+		cancelTaskContext(task)
 
 		// terminate all subtasks
 		for _, subtask := range task.Subtasks {
@@ -31,12 +34,14 @@ func FailedTask(task *model.Task) {
 	channel := GetEventChannel()
 
 	// check if task is regarded to be executing
-	if task.Status == model.TaskStatusExecuting {
+	if task.GetStatus() == model.TaskStatusExecuting {
 		// update status
-		task.Status = model.TaskStatusFailed
+		task.SetStatus(model.TaskStatusFailed)
 
-		// retrigger execution of parent
-		channel <- model.NewEvent(task.Domain, task.Parent, model.EventTypeTaskFailure, task.UUID)
+		// signal failure to parent
+		if task.Parent != "" {
+			channel <- model.NewEvent(task.Domain, task.Parent, model.EventTypeTaskFailure, task.UUID)
+		}
 	}
 }
 
@@ -54,12 +59,17 @@ func TimeoutTask(task *model.Task) {
 	channel := GetEventChannel()
 
 	// check if task is regarded to be executing
-	if task.Status == model.TaskStatusExecuting {
+	if task.GetStatus() == model.TaskStatusExecuting {
 		// update status
-		task.Status = model.TaskStatusTimeout
+		task.SetStatus(model.TaskStatusTimeout)
+
+		// abort the running operation of the task
+		cancelTaskContext(task)
 
 		// signal timeout to parent
-		channel <- model.NewEvent(task.Domain, task.Parent, model.EventTypeTaskTimeout, task.UUID)
+		if task.Parent != "" {
+			channel <- model.NewEvent(task.Domain, task.Parent, model.EventTypeTaskTimeout, task.UUID)
+		}
 	}
 }
 
@@ -71,12 +81,14 @@ func CompletedTask(task *model.Task) {
 	channel := GetEventChannel()
 
 	// check if task is regarded to be executing
-	if task.Status == model.TaskStatusExecuting {
+	if task.GetStatus() == model.TaskStatusExecuting {
 		// update status
-		task.Status = model.TaskStatusCompleted
+		task.SetStatus(model.TaskStatusCompleted)
 
 		// retrigger execution of parent
-		channel <- model.NewEvent(task.Domain, task.Parent, model.EventTypeTaskExecution, task.UUID)
+		if task.Parent != "" {
+			channel <- model.NewEvent(task.Domain, task.Parent, model.EventTypeTaskExecution, task.UUID)
+		}
 	}
 }
 
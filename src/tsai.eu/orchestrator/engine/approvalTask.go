@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// NewApprovalTask creates a new task which waits for a manual approval. The
+// task runs into a timeout if it has not been approved within the expiry
+// (in seconds, 0 = never). The deadlines of its parent tasks do not pass while
+// the approval is pending.
+func NewApprovalTask(domain string, parent string, gate string, expiry int) (model.Task, error) {
+	var task model.Task
+
+	// TODO: check parameters if context exists
+	task.Type = "ApprovalTask"
+	task.Domain = domain
+	task.Architecture = ""
+	task.Component = ""
+	task.Version = ""
+	task.Instance = ""
+	task.State = ""
+	task.UUID = uuid.New().String()
+	task.Parent = parent
+	task.Status = model.TaskStatusInitial
+	task.Phase = 0
+	task.Subtasks = []string{}
+	task.Gate = gate
+	task.Delay = expiry * 1000
+
+	// add handlers
+	BindHandlers(&task)
+
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return task, errors.New("unknown domain")
+	}
+
+	// determine parent node
+	if parent != "" {
+		parentTask, err := d.GetTask(parent)
+		if err != nil {
+			return task, errors.New("unknown parent")
+		}
+
+		// add parent context
+		task.Architecture = parentTask.Architecture
+	}
+
+	// add task to domain
+	err = d.AddTask(&task)
+	if err != nil {
+		return task, err
+	}
+
+	// success
+	return task, nil
+}
+
+//------------------------------------------------------------------------------
+
+// ExecuteApprovalTask starts waiting for the approval of the task.
+func ExecuteApprovalTask(task *model.Task) {
+	// check status
+	status := task.GetStatus()
+
+	if status != model.TaskStatusInitial {
+		return
+	}
+
+	// start the execution
+	startTask(task)
+
+	// the deadlines of the parent tasks do not pass while waiting
+	suspendDeadlines(task)
+
+	// define the expiry of the approval
+	if task.Delay > 0 && task.GetDeadline() == 0 {
+		task.SetDeadline(task.GetStarted() + int64(task.Delay)*int64(time.Millisecond))
+		trackDeadline(task)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// ApproveTask approves a task waiting for a manual approval.
+func ApproveTask(domain string, uuid string) error {
+	task, err := pendingApproval(domain, uuid)
+	if err != nil {
+		return err
+	}
+
+	GetEventChannel() <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskCompletion, "approval")
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// RejectTask rejects a task waiting for a manual approval.
+func RejectTask(domain string, uuid string, reason string) error {
+	task, err := pendingApproval(domain, uuid)
+	if err != nil {
+		return err
+	}
+
+	if reason == "" {
+		reason = "approval has been rejected"
+	}
+	task.SetError(errors.New(reason))
+
+	GetEventChannel() <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, "approval")
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// pendingApproval determines a task which is waiting for a manual approval.
+func pendingApproval(domain string, uuid string) (*model.Task, error) {
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return nil, errors.New("unknown domain")
+	}
+
+	// get task
+	task, err := d.GetTask(uuid)
+	if err != nil {
+		return nil, errors.New("unknown task")
+	}
+
+	if task.Type != "ApprovalTask" {
+		return nil, errors.New("task does not require an approval")
+	}
+
+	if task.GetStatus() != model.TaskStatusExecuting {
+		return nil, errors.New("task is not waiting for an approval")
+	}
+
+	// success
+	return task, nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+func TestApprovalTask(t *testing.T) {
+	tests := []struct {
+		name     string
+		approve  bool
+		expected model.TaskStatus
+	}{
+		{"approve", true, model.TaskStatusCompleted},
+		{"reject", false, model.TaskStatusFailed},
+	}
+
+	DefaultTimeouts["ParallelTask"] = 60
+	defer func() { DefaultTimeouts["ParallelTask"] = 0 }()
+
+	dispatcher := startTestDispatcher()
+	defer stopTestDispatcher(t, dispatcher)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			parent := newTestTask(t, domain, "")
+			created, err := NewApprovalTask(domain.Name, parent.UUID, "gate", 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			approval, _ := domain.GetTask(created.UUID)
+			parent.AddSubtask(approval)
+
+			dispatcher.Channel <- model.NewEvent(domain.Name, parent.UUID, model.EventTypeTaskExecution, "")
+			waitFor(t, "pending approval", func() bool { return approval.GetStatus() == model.TaskStatusExecuting })
+
+			// the deadline of the parent does not pass while the approval is pending
+			deadline := parent.GetDeadline()
+
+			deadlines.Lock()
+			expired := isExpired(parent, deadline+int64(time.Hour))
+			deadlines.Unlock()
+
+			if deadline == 0 || expired {
+				t.Errorf("deadline %d of the parent has not been suspended", deadline)
+			}
+
+			if test.approve {
+				err = ApproveTask(domain.Name, approval.UUID)
+			} else {
+				err = RejectTask(domain.Name, approval.UUID, "")
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			waitFor(t, "parent to finish", func() bool { return parent.GetStatus() > model.TaskStatusExecuting })
+			waitIdle(t, dispatcher)
+
+			if status := parent.GetStatus(); status != test.expected {
+				t.Errorf("expected status %v, got %v", test.expected, status)
+			}
+			if !test.approve && approval.GetError() != "approval has been rejected" {
+				t.Errorf("expected the reason of the rejection")
+			}
+
+			// the deadline of the parent has been extended by the pending time
+			deadlines.Lock()
+			_, suspended := deadlines.Suspended[domain.Name+"/"+parent.UUID]
+			deadlines.Unlock()
+
+			if suspended || parent.GetDeadline() <= deadline {
+				t.Errorf("deadline of the parent has not been resumed")
+			}
+
+			// finished approvals can not be approved
+			if err := ApproveTask(domain.Name, approval.UUID); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
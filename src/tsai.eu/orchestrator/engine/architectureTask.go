@@ -2,21 +2,41 @@ package engine
 
 import (
 	"errors"
-  "fmt"
+
 	"github.com/google/uuid"
 	"tsai.eu/orchestrator/model"
 )
 
 //------------------------------------------------------------------------------
 
-// NewArchitectureTask creates a new task
+// NewArchitectureTask creates a new task which moves a domain towards an architecture
 func NewArchitectureTask(domain string, parent string, architecture *model.Architecture) (model.Task, error) {
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return model.Task{}, errors.New("unknown domain")
+	}
+
+	// determine the required changes
+	plan, err := NewPlan(d, architecture)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	// success
+	return NewPlanTask(parent, plan)
+}
+
+//------------------------------------------------------------------------------
+
+// NewPlanTask creates a new task which executes exactly the changes of a plan
+func NewPlanTask(parent string, plan *Plan) (model.Task, error) {
 	var task model.Task
 
 	// TODO: check parameters if context exists
 	task.Type = "ArchitectureTask"
-	task.Domain = domain
-	task.Architecture = architecture.Name
+	task.Domain = plan.Domain
+	task.Architecture = plan.Architecture
 	task.Component = ""
 	task.Version = ""
 	task.Instance = ""
@@ -28,14 +48,10 @@ func NewArchitectureTask(domain string, parent string, architecture *model.Archi
 	task.Subtasks = []string{}
 
 	// add handlers
-	task.SetExecute(ExecuteParallelTask)
-	task.SetTerminate(TerminateTask)
-	task.SetFailed(FailedTask)
-	task.SetTimeout(TimeoutTask)
-	task.SetCompleted(CompletedTask)
+	BindHandlers(&task)
 
 	// get domain
-	d, err := model.GetModel().GetDomain(domain)
+	d, err := model.GetModel().GetDomain(plan.Domain)
 	if err != nil {
 		return task, errors.New("unknown domain")
 	}
@@ -46,20 +62,52 @@ func NewArchitectureTask(domain string, parent string, architecture *model.Archi
 		return task, err
 	}
 
-	// construct all required subtasks (one for each service)
-	architecture.Services.RLock()
-	for service := range architecture.Services.Map {
-		subtask, err := NewServiceTask(domain, task.UUID, architecture.Name, service)
+	// remember the architecture as the desired state of the domain
+	d.Architecture = plan.Architecture
+
+	if architecture, err := d.GetArchitecture(plan.Architecture); err == nil {
+		rememberArchitecture(&task, architecture)
+	}
+
+	// construct all required subtasks (one parallel task for each wave of
+	// services followed by the approvals of the wave)
+	for index, services := range plan.Waves {
+		wave, err := NewParallelTask(plan.Domain, task.UUID, []string{})
 		if err != nil {
-			return task, errors.New("unable to create subtask for a required service")
+			return task, errors.New("unable to create subtask for a wave of services")
 		}
 
-		task.AddSubtask(&subtask)
-	}
-	architecture.Services.RUnlock()
+		waveTask, _ := d.GetTask(wave.UUID)
+		for _, service := range services {
+			servicePlan, found := plan.Services[service]
+			if !found {
+				servicePlan = &ServicePlan{Service: service, Actions: []*PlanAction{}}
+			}
+
+			subtask, err := NewServiceTask(plan.Domain, wave.UUID, plan.Architecture, servicePlan)
+			if err != nil {
+				return task, errors.New("unable to create subtask for a required service")
+			}
 
-	fmt.Println(task.Subtasks)
-	fmt.Println(d.GetTask(task.UUID))
+			waveTask.AddSubtask(&subtask)
+		}
+
+		task.AddSubtask(&wave)
+
+		// wait for the approval of the wave if required
+		for _, gate := range plan.Gates {
+			if gate.Wave != index {
+				continue
+			}
+
+			approval, err := NewApprovalTask(plan.Domain, task.UUID, gate.Name, gate.Expiry)
+			if err != nil {
+				return task, errors.New("unable to create subtask for an approval gate")
+			}
+
+			task.AddSubtask(&approval)
+		}
+	}
 
 	// success
 	return task, nil
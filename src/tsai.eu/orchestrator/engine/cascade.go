@@ -0,0 +1,321 @@
+package engine
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"tsai.eu/orchestrator/model"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+
+// cascades keeps track of the latest cascade task per domain.
+var cascades = struct {
+	sync.Mutex
+	Tasks map[string]string // domain -> uuid of the cascade task
+}{Tasks: map[string]string{}}
+
+//------------------------------------------------------------------------------
+
+// DependentGraph captures which components depend on a component (the reverse
+// of the dependency graph) as defined by all template variants of a domain.
+type DependentGraph struct {
+	Dependents map[string]map[string]bool // dependents of a component (component -> dependents)
+}
+
+//------------------------------------------------------------------------------
+
+// NewDependentGraph determines the reverse dependency graph of a domain.
+func NewDependentGraph(domain *model.Domain) *DependentGraph {
+	graph := DependentGraph{Dependents: map[string]map[string]bool{}}
+
+	templates, _ := domain.ListTemplates()
+	for _, name := range templates {
+		template, err := domain.GetTemplate(name)
+		if err != nil {
+			continue
+		}
+
+		versions, _ := template.ListVariants()
+		for _, version := range versions {
+			variant, err := template.GetVariant(version)
+			if err != nil {
+				continue
+			}
+
+			dependencies, _ := variant.ListDependencies()
+			for _, dependencyName := range dependencies {
+				dependency, _ := variant.GetDependency(dependencyName)
+
+				// ignore self references
+				if dependency.Component == name {
+					continue
+				}
+
+				if _, found := graph.Dependents[dependency.Component]; !found {
+					graph.Dependents[dependency.Component] = map[string]bool{}
+				}
+				graph.Dependents[dependency.Component][name] = true
+			}
+		}
+	}
+
+	return &graph
+}
+
+//------------------------------------------------------------------------------
+
+// Affected determines the components together with all of their direct and
+// indirect dependents.
+func (graph *DependentGraph) Affected(components []string) map[string]bool {
+	affected := map[string]bool{}
+
+	queue := append([]string{}, components...)
+	for len(queue) > 0 {
+		component := queue[0]
+		queue = queue[1:]
+
+		if affected[component] {
+			continue
+		}
+		affected[component] = true
+
+		for dependent := range graph.Dependents[component] {
+			queue = append(queue, dependent)
+		}
+	}
+
+	return affected
+}
+
+//------------------------------------------------------------------------------
+
+// Waves orders a set of components along the dependency graph: components
+// precede the components depending on them.
+func (graph *DependentGraph) Waves(components map[string]bool) ([][]string, error) {
+	serviceGraph := ServiceGraph{
+		Nodes:  map[string]bool{},
+		Edges:  map[string]map[string]bool{},
+		Levels: map[string]int{},
+	}
+
+	for component := range components {
+		serviceGraph.Nodes[component] = false
+		serviceGraph.Edges[component] = map[string]bool{}
+	}
+
+	// invert the dependents of the components into their dependencies
+	for component := range components {
+		for dependent := range graph.Dependents[component] {
+			if components[dependent] {
+				serviceGraph.Edges[dependent][component] = true
+			}
+		}
+	}
+
+	err := serviceGraph.determineLevels()
+	if err != nil {
+		return nil, err
+	}
+
+	// success
+	return serviceGraph.Waves(), nil
+}
+
+//------------------------------------------------------------------------------
+
+// hasStaleDependencies checks if the endpoints of the dependencies of an
+// instance differ from the current endpoints of the components it depends on.
+func hasStaleDependencies(domain *model.Domain, component *model.Component, instance *model.Instance) bool {
+	if instance.State == model.GetStateMachine(component.Type).Initial {
+		return false
+	}
+
+	return !util.AreEqual(instance.GetDependencies(), model.DetermineDependencies(domain, component, instance))
+}
+
+//------------------------------------------------------------------------------
+
+// planCascade determines the waves of components whose instances need to be
+// reconfigured since the endpoints of their dependencies have changed. The
+// dependents of these components are included since their configuration may
+// change as well. Components without stale instances result in no waves.
+func planCascade(domain *model.Domain) ([][]string, error) {
+	// determine the components with stale dependency endpoints
+	stale := []string{}
+
+	components, _ := domain.ListComponents()
+	sort.Strings(components)
+	for _, name := range components {
+		component, err := domain.GetComponent(name)
+		if err != nil {
+			continue
+		}
+
+		instances, _ := component.ListInstances()
+		for _, uuid := range instances {
+			instance, err := component.GetInstance(uuid)
+			if err == nil && hasStaleDependencies(domain, component, instance) {
+				stale = append(stale, name)
+				break
+			}
+		}
+	}
+
+	if len(stale) == 0 {
+		return [][]string{}, nil
+	}
+
+	// order the stale components and their dependents along the graph
+	graph := NewDependentGraph(domain)
+
+	affected := map[string]bool{}
+	for component := range graph.Affected(stale) {
+		if _, err := domain.GetComponent(component); err == nil {
+			affected[component] = true
+		}
+	}
+
+	return graph.Waves(affected)
+}
+
+//------------------------------------------------------------------------------
+
+// NewCascadeTask creates a new task which reconfigures the instances of a
+// domain whose dependency endpoints have changed. The components are processed
+// in waves along the dependency graph and each instance is kept in its
+// current state.
+func NewCascadeTask(domain string, waves [][]string) (model.Task, error) {
+	var task model.Task
+
+	task.Type = "CascadeTask"
+	task.Domain = domain
+	task.UUID = uuid.New().String()
+	task.Status = model.TaskStatusInitial
+	task.Phase = 0
+	task.Subtasks = []string{}
+
+	// add handlers
+	BindHandlers(&task)
+
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return task, errors.New("unknown domain")
+	}
+
+	// add task to domain
+	err = d.AddTask(&task)
+	if err != nil {
+		return task, err
+	}
+
+	// construct a parallel task for each wave with an instance task for each
+	// instance of the components of the wave
+	for _, components := range waves {
+		wave, err := NewParallelTask(domain, task.UUID, []string{})
+		if err != nil {
+			return task, errors.New("unable to create subtask for a wave of components")
+		}
+		task.AddSubtask(&wave)
+
+		waveTask, _ := d.GetTask(wave.UUID)
+		for _, name := range components {
+			component, err := d.GetComponent(name)
+			if err != nil {
+				continue
+			}
+
+			instances, _ := component.ListInstances()
+			sort.Strings(instances)
+			for _, uuid := range instances {
+				instance, err := component.GetInstance(uuid)
+				if err != nil || instance.State == model.GetStateMachine(component.Type).Initial {
+					continue
+				}
+
+				subtask, err := NewInstanceTask(domain, waveTask.UUID, "", name, instance.Version, uuid, instance.State)
+				if err != nil {
+					return task, err
+				}
+				waveTask.AddSubtask(&subtask)
+			}
+		}
+	}
+
+	// success
+	return task, nil
+}
+
+//------------------------------------------------------------------------------
+
+// cascadeReconfiguration schedules the reconfiguration of the dependents of
+// components whose endpoints have been changed by a completed task. Only
+// top-level tasks and rollbacks trigger a cascade, cascades do not trigger
+// further cascades and a domain has at most one executing cascade. The
+// instances of the domain are examined in the background.
+func cascadeReconfiguration(task *model.Task) {
+	if task.GetStatus() != model.TaskStatusCompleted || task.Type == "CascadeTask" {
+		return
+	}
+
+	if task.Parent != "" && task.Compensates == "" {
+		return
+	}
+
+	go startCascade(task)
+}
+
+//------------------------------------------------------------------------------
+
+// startCascade creates and starts a cascade task for a domain unless one is
+// already executing.
+func startCascade(task *model.Task) {
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		return
+	}
+
+	cascades.Lock()
+	defer cascades.Unlock()
+
+	if cascadeExecuting(domain) {
+		return
+	}
+
+	waves, err := planCascade(domain)
+	if err != nil || len(waves) == 0 {
+		return
+	}
+
+	cascade, err := NewCascadeTask(domain.Name, waves)
+	if err != nil {
+		return
+	}
+	cascades.Tasks[domain.Name] = cascade.UUID
+
+	GetEventChannel() <- model.NewEvent(cascade.Domain, cascade.UUID, model.EventTypeTaskExecution, task.UUID)
+}
+
+//------------------------------------------------------------------------------
+
+// cascadeExecuting checks if the latest cascade task of a domain has not
+// finished yet.
+func cascadeExecuting(domain *model.Domain) bool {
+	uuid, found := cascades.Tasks[domain.Name]
+	if !found {
+		return false
+	}
+
+	task, err := domain.GetTask(uuid)
+	if err != nil {
+		return false
+	}
+
+	return task.GetStatus() <= model.TaskStatusExecuting
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,205 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+func TestDependentGraphAffected(t *testing.T) {
+	domain := newTestDomain(t, testDependencies)
+	defer model.GetModel().DeleteDomain(domain.Name)
+
+	graph := NewDependentGraph(domain)
+
+	tests := []struct {
+		name       string
+		components []string
+		expected   map[string]bool
+	}{
+		{"root", []string{"net"}, map[string]bool{"net": true, "db": true, "app": true, "web": true}},
+		{"middle", []string{"app"}, map[string]bool{"app": true, "web": true}},
+		{"leaf", []string{"web"}, map[string]bool{"web": true}},
+		{"unrelated", []string{"mon"}, map[string]bool{"mon": true}},
+		{"multiple", []string{"web", "mon"}, map[string]bool{"web": true, "mon": true}},
+		{"unknown", []string{"dns"}, map[string]bool{"dns": true}},
+		{"none", []string{}, map[string]bool{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			affected := graph.Affected(test.components)
+			if !reflect.DeepEqual(affected, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, affected)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestDependentGraphWaves(t *testing.T) {
+	tests := []struct {
+		name         string
+		dependencies map[string][]string
+		components   map[string]bool
+		expected     [][]string
+		fails        bool
+	}{
+		{
+			name:         "chain",
+			dependencies: testDependencies,
+			components:   map[string]bool{"net": true, "db": true, "app": true, "web": true},
+			expected:     [][]string{{"net"}, {"db"}, {"app"}, {"web"}},
+		},
+		{
+			name:         "subset",
+			dependencies: testDependencies,
+			components:   map[string]bool{"net": true, "app": true, "mon": true},
+			expected:     [][]string{{"mon", "net"}, {"app"}},
+		},
+		{
+			name:         "independent",
+			dependencies: testDependencies,
+			components:   map[string]bool{"web": true, "mon": true},
+			expected:     [][]string{{"mon", "web"}},
+		},
+		{
+			name:         "empty",
+			dependencies: testDependencies,
+			components:   map[string]bool{},
+			expected:     [][]string{},
+		},
+		{
+			name:         "cycle",
+			dependencies: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}},
+			components:   map[string]bool{"a": true, "b": true, "c": true},
+			fails:        true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, test.dependencies)
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			waves, err := NewDependentGraph(domain).Waves(test.components)
+			if test.fails {
+				if err == nil {
+					t.Errorf("expected an error, got %v", waves)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(waves, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, waves)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestPlanCascade(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string // component publishing a new endpoint
+		removed  string // component which is removed
+		initial  string // component whose instances are reset to the initial state
+		expected [][]string
+	}{
+		{name: "unchanged", expected: [][]string{}},
+		{name: "root endpoint", endpoint: "net", expected: [][]string{{"db"}, {"app"}, {"web"}}},
+		{name: "middle endpoint", endpoint: "app", expected: [][]string{{"web"}}},
+		{name: "leaf endpoint", endpoint: "web", expected: [][]string{}},
+		{name: "removed dependency", removed: "db", expected: [][]string{{"app"}, {"web"}}},
+		{name: "initial instances", endpoint: "net", initial: "db", expected: [][]string{{"app"}, {"web"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, testDependencies)
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			for _, name := range []string{"net", "db", "app", "web", "mon"} {
+				addTestInstance(t, domain, name, model.ActiveState, name+"-1")
+			}
+
+			if component, err := domain.GetComponent(test.endpoint); err == nil {
+				component.AddEndpoint("1.0.0", test.endpoint+"-2")
+			}
+			if component, err := domain.GetComponent(test.initial); err == nil {
+				instances, _ := component.ListInstances()
+				for _, uuid := range instances {
+					instance, _ := component.GetInstance(uuid)
+					instance.State = model.InitialState
+				}
+			}
+			if test.removed != "" {
+				domain.DeleteComponent(test.removed)
+			}
+
+			waves, err := planCascade(domain)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(waves, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, waves)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestCascadeExecuting(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   model.TaskStatus
+		expected bool
+	}{
+		{"initial", model.TaskStatusInitial, true},
+		{"executing", model.TaskStatusExecuting, true},
+		{"completed", model.TaskStatusCompleted, false},
+		{"failed", model.TaskStatusFailed, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, testDependencies)
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			if cascadeExecuting(domain) {
+				t.Fatalf("unexpected cascade without a task")
+			}
+
+			task, err := NewCascadeTask(domain.Name, [][]string{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			stored, _ := domain.GetTask(task.UUID)
+			stored.SetStatus(test.status)
+
+			cascades.Lock()
+			cascades.Tasks[domain.Name] = task.UUID
+			cascades.Unlock()
+
+			defer func() {
+				cascades.Lock()
+				delete(cascades.Tasks, domain.Name)
+				cascades.Unlock()
+			}()
+
+			if executing := cascadeExecuting(domain); executing != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, executing)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
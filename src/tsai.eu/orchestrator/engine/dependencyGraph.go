@@ -0,0 +1,242 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// ServiceGraph captures the dependencies between the services of a domain.
+type ServiceGraph struct {
+	Nodes    map[string]bool            // services and the information if they are to be torn down
+	Edges    map[string]map[string]bool // dependencies of a service (service -> dependencies)
+	Levels   map[string]int             // topological level of a service
+	MaxLevel int                        // highest topological level
+}
+
+//------------------------------------------------------------------------------
+
+// isActiveService determines if a service has at least one setup which requires running instances.
+func isActiveService(domain *model.Domain, service *model.Service) bool {
+	if service == nil {
+		return false
+	}
+
+	// determine the initial state of the component type
+	initial := model.DefaultStateMachine().Initial
+	if template, err := domain.GetTemplate(service.Name); err == nil {
+		initial = model.GetStateMachine(template.Type).Initial
+	}
+
+	setups, _ := service.ListSetups()
+	for _, name := range setups {
+		setup, _ := service.GetSetup(name)
+		if setup.Size > 0 && setup.State != initial {
+			return true
+		}
+	}
+
+	return false
+}
+
+//------------------------------------------------------------------------------
+
+// addVariantDependencies adds the dependencies of a template variant to the graph.
+func (graph *ServiceGraph) addVariantDependencies(domain *model.Domain, service string, version string) {
+	template, err := domain.GetTemplate(service)
+	if err != nil {
+		return
+	}
+
+	variant, err := template.GetVariant(version)
+	if err != nil {
+		return
+	}
+
+	dependencies, _ := variant.ListDependencies()
+	for _, name := range dependencies {
+		dependency, _ := variant.GetDependency(name)
+
+		// ignore self references
+		if dependency.Component != service {
+			graph.Edges[service][dependency.Component] = true
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// NewServiceGraph determines the dependency graph of all services which are
+// either part of an architecture or are currently deployed within a domain.
+func NewServiceGraph(domain *model.Domain, architecture *model.Architecture) (*ServiceGraph, error) {
+	graph := ServiceGraph{
+		Nodes:  map[string]bool{},
+		Edges:  map[string]map[string]bool{},
+		Levels: map[string]int{},
+	}
+
+	// services of the architecture depend on the target versions
+	services, _ := architecture.ListServices()
+	for _, name := range services {
+		service, _ := architecture.GetService(name)
+
+		graph.Nodes[name] = !isActiveService(domain, service)
+		graph.Edges[name] = map[string]bool{}
+
+		setups, _ := service.ListSetups()
+		for _, setupName := range setups {
+			setup, _ := service.GetSetup(setupName)
+
+			graph.addVariantDependencies(domain, name, setup.Version)
+		}
+	}
+
+	// deployed components which are not part of the architecture need to be torn
+	// down and depend on the versions of their current instances
+	components, _ := domain.ListComponents()
+	for _, name := range components {
+		if _, found := graph.Nodes[name]; found {
+			continue
+		}
+
+		graph.Nodes[name] = true
+		graph.Edges[name] = map[string]bool{}
+
+		component, _ := domain.GetComponent(name)
+		instances, _ := component.ListInstances()
+		for _, uuid := range instances {
+			instance, _ := component.GetInstance(uuid)
+
+			graph.addVariantDependencies(domain, name, instance.Version)
+		}
+	}
+
+	// dependencies on components outside of the graph are not relevant for the ordering
+	for _, dependencies := range graph.Edges {
+		for dependency := range dependencies {
+			if _, found := graph.Nodes[dependency]; !found {
+				delete(dependencies, dependency)
+			}
+		}
+	}
+
+	// determine the topological levels
+	err := graph.determineLevels()
+	if err != nil {
+		return nil, err
+	}
+
+	// success
+	return &graph, nil
+}
+
+//------------------------------------------------------------------------------
+
+// determineLevels calculates the topological level of each service (Kahn's algorithm).
+func (graph *ServiceGraph) determineLevels() error {
+	// count the unresolved dependencies of each service
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	for node := range graph.Nodes {
+		pending[node] = len(graph.Edges[node])
+		for dependency := range graph.Edges[node] {
+			dependents[dependency] = append(dependents[dependency], node)
+		}
+	}
+
+	// start with all services without dependencies
+	queue := []string{}
+	for node, count := range pending {
+		if count == 0 {
+			queue = append(queue, node)
+			graph.Levels[node] = 0
+		}
+	}
+
+	// resolve the dependencies level by level
+	resolved := 0
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		resolved++
+
+		for _, dependent := range dependents[node] {
+			if graph.Levels[node]+1 > graph.Levels[dependent] {
+				graph.Levels[dependent] = graph.Levels[node] + 1
+			}
+
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	// all services need to be resolved otherwise a cycle exists
+	if resolved != len(graph.Nodes) {
+		cyclic := []string{}
+		for node, count := range pending {
+			if count > 0 {
+				cyclic = append(cyclic, node)
+			}
+		}
+		sort.Strings(cyclic)
+
+		return errors.Errorf("cyclic dependencies between services: %v", cyclic)
+	}
+
+	// determine the highest level
+	graph.MaxLevel = 0
+	for _, level := range graph.Levels {
+		if level > graph.MaxLevel {
+			graph.MaxLevel = level
+		}
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// Waves determines the groups of services which can be processed in parallel:
+// services which are brought up are ordered along their dependencies, services
+// which are torn down are ordered in the reverse direction and succeed all
+// other services.
+func (graph *ServiceGraph) Waves() [][]string {
+	waves := [][]string{}
+
+	// collect the services of a level
+	collect := func(level int, teardown bool) []string {
+		wave := []string{}
+		for node, down := range graph.Nodes {
+			if down == teardown && graph.Levels[node] == level {
+				wave = append(wave, node)
+			}
+		}
+		sort.Strings(wave)
+		return wave
+	}
+
+	// bring up services in topological order
+	for level := 0; level <= graph.MaxLevel; level++ {
+		if wave := collect(level, false); len(wave) > 0 {
+			waves = append(waves, wave)
+		}
+	}
+
+	// tear down services in reverse topological order
+	for level := graph.MaxLevel; level >= 0; level-- {
+		if wave := collect(level, true); len(wave) > 0 {
+			waves = append(waves, wave)
+		}
+	}
+
+	// success
+	return waves
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,304 @@
+package engine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// testDependencies describes a chain of components (net <- db <- app <- web)
+// together with an unrelated component.
+var testDependencies = map[string][]string{
+	"net": {},
+	"db":  {"net"},
+	"app": {"db", "net"},
+	"web": {"app"},
+	"mon": {},
+}
+
+//------------------------------------------------------------------------------
+
+// newTestDomain registers a domain named after the test. Each component gets a
+// template with the variant "1.0.0" which depends on the listed components.
+func newTestDomain(t *testing.T, dependencies map[string][]string) *model.Domain {
+	name := strings.Replace(t.Name(), "/", "_", -1)
+
+	// replace leftovers of previous runs
+	m := model.GetModel()
+	m.DeleteDomain(name)
+
+	domain, _ := model.NewDomain(name)
+	if err := m.AddDomain(domain); err != nil {
+		t.Fatalf("unable to add domain: %v", err)
+	}
+
+	for component, services := range dependencies {
+		template, _ := model.NewTemplate(component, "test")
+		variant, _ := model.NewVariant("1.0.0", component+"-configuration")
+
+		for _, service := range services {
+			dependency, _ := model.NewDependency(service, "service", service, "1.0.0")
+			variant.AddDependency(dependency)
+		}
+
+		template.AddVariant(variant)
+		domain.AddTemplate(template)
+	}
+
+	return domain
+}
+
+//------------------------------------------------------------------------------
+
+// addTestInstance adds an instance of version "1.0.0" in the given state to a
+// component (created on demand). The component publishes the endpoint and the
+// instance records the current endpoints of its dependencies.
+func addTestInstance(t *testing.T, domain *model.Domain, name string, state string, endpoint string) *model.Instance {
+	component, err := domain.GetComponent(name)
+	if err != nil {
+		component, _ = model.NewComponent(name, "test")
+		domain.AddComponent(component)
+	}
+
+	if endpoint != "" {
+		component.AddEndpoint("1.0.0", endpoint)
+	}
+
+	instance, _ := model.NewInstance("1.0.0")
+	instance.State = state
+	instance.Endpoint = endpoint
+	instance.SetDependencies(model.DetermineDependencies(domain, component, instance))
+
+	if err := component.AddInstance(instance); err != nil {
+		t.Fatalf("unable to add instance: %v", err)
+	}
+
+	return instance
+}
+
+//------------------------------------------------------------------------------
+
+// newTestGraph creates a service graph from the dependencies of the services
+// and the services which are to be torn down.
+func newTestGraph(edges map[string][]string, teardown ...string) *ServiceGraph {
+	graph := ServiceGraph{
+		Nodes:  map[string]bool{},
+		Edges:  map[string]map[string]bool{},
+		Levels: map[string]int{},
+	}
+
+	for node, dependencies := range edges {
+		graph.Nodes[node] = false
+		graph.Edges[node] = map[string]bool{}
+		for _, dependency := range dependencies {
+			graph.Edges[node][dependency] = true
+		}
+	}
+
+	for _, node := range teardown {
+		graph.Nodes[node] = true
+	}
+
+	return &graph
+}
+
+//------------------------------------------------------------------------------
+
+func TestServiceGraphLevels(t *testing.T) {
+	tests := []struct {
+		name     string
+		edges    map[string][]string
+		levels   map[string]int
+		maxLevel int
+		fails    bool
+	}{
+		{
+			name:     "empty",
+			edges:    map[string][]string{},
+			levels:   map[string]int{},
+			maxLevel: 0,
+		},
+		{
+			name:     "independent",
+			edges:    map[string][]string{"a": {}, "b": {}},
+			levels:   map[string]int{"a": 0, "b": 0},
+			maxLevel: 0,
+		},
+		{
+			name:     "chain",
+			edges:    map[string][]string{"a": {}, "b": {"a"}, "c": {"b"}},
+			levels:   map[string]int{"a": 0, "b": 1, "c": 2},
+			maxLevel: 2,
+		},
+		{
+			name:     "longest path",
+			edges:    map[string][]string{"a": {}, "b": {"a"}, "c": {"a", "b"}, "d": {}},
+			levels:   map[string]int{"a": 0, "b": 1, "c": 2, "d": 0},
+			maxLevel: 2,
+		},
+		{
+			name:  "cycle",
+			edges: map[string][]string{"a": {"c"}, "b": {"a"}, "c": {"b"}},
+			fails: true,
+		},
+		{
+			name:  "cycle behind a resolved service",
+			edges: map[string][]string{"a": {}, "b": {"a", "c"}, "c": {"b"}},
+			fails: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			graph := newTestGraph(test.edges)
+
+			err := graph.determineLevels()
+			if test.fails {
+				if err == nil {
+					t.Errorf("expected an error, got %v", graph.Levels)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(graph.Levels, test.levels) {
+				t.Errorf("expected levels %v, got %v", test.levels, graph.Levels)
+			}
+			if graph.MaxLevel != test.maxLevel {
+				t.Errorf("expected max level %d, got %d", test.maxLevel, graph.MaxLevel)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestServiceGraphWaves(t *testing.T) {
+	tests := []struct {
+		name     string
+		edges    map[string][]string
+		teardown []string
+		expected [][]string
+	}{
+		{
+			name:     "empty",
+			edges:    map[string][]string{},
+			expected: [][]string{},
+		},
+		{
+			name:     "bring up",
+			edges:    map[string][]string{"a": {}, "b": {"a"}, "c": {"a"}, "d": {"b", "c"}},
+			expected: [][]string{{"a"}, {"b", "c"}, {"d"}},
+		},
+		{
+			name:     "tear down",
+			edges:    map[string][]string{"a": {}, "b": {"a"}, "c": {"b"}},
+			teardown: []string{"a", "b", "c"},
+			expected: [][]string{{"c"}, {"b"}, {"a"}},
+		},
+		{
+			name:     "mixed",
+			edges:    map[string][]string{"a": {}, "b": {"a"}, "c": {"a"}, "d": {"c"}},
+			teardown: []string{"c", "d"},
+			expected: [][]string{{"a"}, {"b"}, {"d"}, {"c"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			graph := newTestGraph(test.edges, test.teardown...)
+
+			if err := graph.determineLevels(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			waves := graph.Waves()
+			if !reflect.DeepEqual(waves, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, waves)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestNewServiceGraph(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []string
+		deployed []string
+		expected [][]string
+		fails    bool
+		cyclic   bool
+	}{
+		{
+			name:     "architecture",
+			services: []string{"net", "db", "app", "web"},
+			expected: [][]string{{"net"}, {"db"}, {"app"}, {"web"}},
+		},
+		{
+			name:     "external dependencies",
+			services: []string{"app", "web"},
+			expected: [][]string{{"app"}, {"web"}},
+		},
+		{
+			name:     "teardown of deployed components",
+			services: []string{"net", "db"},
+			deployed: []string{"app", "web", "mon"},
+			expected: [][]string{{"net"}, {"db"}, {"web"}, {"app"}, {"mon"}},
+		},
+		{
+			name:     "cycle",
+			services: []string{"net", "db"},
+			cyclic:   true,
+			fails:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dependencies := testDependencies
+			if test.cyclic {
+				dependencies = map[string][]string{"net": {"db"}, "db": {"net"}}
+			}
+
+			domain := newTestDomain(t, dependencies)
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			architecture, _ := model.NewArchitecture("architecture")
+			for _, name := range test.services {
+				service, _ := model.NewService(name)
+				setup, _ := model.NewSetup("1.0.0", "1.0.0", model.ActiveState, 1)
+				service.AddSetup(setup)
+				architecture.AddService(service)
+			}
+
+			for _, name := range test.deployed {
+				addTestInstance(t, domain, name, model.ActiveState, "")
+			}
+
+			graph, err := NewServiceGraph(domain, architecture)
+			if test.fails {
+				if err == nil {
+					t.Errorf("expected an error, got %v", graph.Waves())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			waves := graph.Waves()
+			if !reflect.DeepEqual(waves, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, waves)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
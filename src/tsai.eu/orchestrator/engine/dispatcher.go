@@ -2,6 +2,7 @@ package engine
 
 import (
 	"fmt"
+	"time"
 
 	"tsai.eu/orchestrator/model"
 )
@@ -10,9 +11,9 @@ import (
 
 // Dispatcher receives events from a channel and triggers a task coroutine.
 type Dispatcher struct {
-	Model   *model.Model           // repository
-	Channel chan model.Event       // the channel for event notification
-	Tasks   map[string]*model.Task // map of running tasks
+	Model   *model.Model     // repository
+	Channel chan model.Event // the channel for event notification
+	Pool    *WorkerPool      // workers executing the event handlers
 }
 
 //------------------------------------------------------------------------------
@@ -27,7 +28,7 @@ func StartDispatcher(m *model.Model) chan model.Event {
 	dispatcher := Dispatcher{
 		Model:   m,
 		Channel: channel,
-		Tasks:   map[string]*model.Task{},
+		Pool:    GetWorkerPool(),
 	}
 
 	// start the dispatcher
@@ -40,64 +41,136 @@ func StartDispatcher(m *model.Model) chan model.Event {
 
 // Run starts the dispatcher loop receiving events and triggering tasks.
 func (d *Dispatcher) Run() {
+	// check deadlines of running tasks periodically
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
 	// loop until exit is requested
 	for {
+		select {
 		// get next event
-		event := <-d.Channel
+		case event := <-d.Channel:
+			// terminate if domain is empty = exit request
+			if event.Domain == "" {
+				return
+			}
 
-		// terminate if domain is empty = exit request
-		if event.Domain == "" {
-			return
-		}
+			d.dispatch(event)
 
-		// get corresponding domain from the model
-		domain, err := d.Model.GetDomain(event.Domain)
-		if err != nil {
-			// TODO: log unknown domain
-			continue
+		// check for tasks which have exceeded their deadline
+		case <-ticker.C:
+			d.checkDeadlines()
 		}
+	}
+}
 
-		// save event
-		domain.AddEvent(&event)
+//------------------------------------------------------------------------------
 
-		// get task
-		task, err := domain.GetTask(event.Task)
-		if err != nil {
-			fmt.Println(err)
-			// TODO: log unknown task
-			continue
-		}
+// dispatch handles an event by triggering the corresponding task handler.
+func (d *Dispatcher) dispatch(event model.Event) {
+	// get corresponding domain from the model
+	domain, err := d.Model.GetDomain(event.Domain)
+	if err != nil {
+		// TODO: log unknown domain
+		return
+	}
+
+	// save and publish event
+	recordEvent(domain, &event)
+	recordActivity(&event)
+
+	// get task
+	task, err := domain.GetTask(event.Task)
+	if err != nil {
+		recordError(domain, &event, err)
+		return
+	}
+
+	// resolve the behaviour of the task by its type
+	taskType, err := GetTaskType(task.Type)
+	if err != nil {
+		recordError(domain, &event, err)
+		return
+	}
+
+	// hold back tasks which must not be started while an ancestor is paused
+	if holdEvent(domain, task, event) {
+		return
+	}
 
-		// determine action by type of event
-		// Event types: execute, completed, failed, timeout, terminate
-		// Task types can be:
-		// - set component state
-		// - set instance state
-		// - transition component
-		// - transition instance
-		// - parallel execute tasks
-		// - sequentially execute tasks
-		switch event.Type {
-		// execute the task
-		case model.EventTypeTaskExecution:
-			go task.Execute()
-
-		// handle task completion
-		case model.EventTypeTaskCompletion:
-			go task.Completed()
-
-		// handle task failure
-		case model.EventTypeTaskFailure:
-			go task.Failed()
-
-		// handle timeout of a task
-		case model.EventTypeTaskTimeout:
-			go task.Timeout()
-
-		// handle termination of a task
-		case model.EventTypeTaskTermination:
-			go task.Terminate()
+	// determine action by type of event
+	// Event types: execute, completed, failed, timeout, terminate
+	switch event.Type {
+	// execute the task
+	case model.EventTypeTaskExecution:
+		journalNewTask(task)
+		d.Pool.Submit(task, func() { taskType.Execute(task) })
+
+	// handle task completion
+	case model.EventTypeTaskCompletion:
+		d.Pool.Submit(task, func() { taskType.Completed(task) })
+
+	// handle task failure
+	case model.EventTypeTaskFailure:
+		d.Pool.Submit(task, func() { taskType.Failed(task) })
+
+	// handle timeout of a task (the running operation of the task is
+	// cancelled right away since the handler waits for it to return)
+	case model.EventTypeTaskTimeout:
+		cancelTaskContext(task)
+		d.Pool.SubmitControl(task, func() { taskType.Timeout(task) })
+
+	// handle termination of a task
+	case model.EventTypeTaskTermination:
+		cancelTaskContext(task)
+		d.Pool.SubmitControl(task, func() { taskType.Terminate(task) })
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// recordError records an event of a domain which could not be handled.
+func recordError(domain *model.Domain, event *model.Event, err error) {
+	failure := model.NewEvent(domain.Name, event.Task, model.EventTypeError, "dispatcher")
+	failure.Detail = fmt.Sprintf("%s event of task '%s' can not be handled: %s", event.Type, event.Task, err)
+
+	recordEvent(domain, &failure)
+}
+
+//------------------------------------------------------------------------------
+
+// handle executes an event handler of a task, journals the resulting state of
+// the task and concludes tasks which have finished (including the
+// reconfiguration of dependents of changed endpoints).
+func handle(task *model.Task, handler func()) {
+	handler()
+
+	if task.GetStatus() > model.TaskStatusExecuting && task.GetFinished() == 0 {
+		task.SetFinished(time.Now().UnixNano())
+	}
+
+	journalTask(task)
+
+	if task.GetStatus() > model.TaskStatusExecuting {
+		GetLockManager().Release(task)
+
+		switch task.Type {
+		case "ApprovalTask":
+			resumeDeadlines(task)
+		case "ArchitectureTask":
+			concludeArchitectureTask(task)
 		}
+
+		cascadeReconfiguration(task)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// checkDeadlines emits timeout events for all executing tasks which have exceeded their deadline.
+func (d *Dispatcher) checkDeadlines() {
+	for _, task := range expiredTasks(time.Now().UnixNano()) {
+		d.dispatch(model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskTimeout, task.UUID))
 	}
 }
 
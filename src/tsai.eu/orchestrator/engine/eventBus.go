@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// EventFilter selects the events delivered to a subscription. Empty fields
+// match any event.
+type EventFilter struct {
+	Domain string            // domain of the events
+	Task   string            // uuid of the task addressed by the events
+	Types  []model.EventType // types of the events
+}
+
+//------------------------------------------------------------------------------
+
+// Matches checks if an event is selected by the filter.
+func (filter *EventFilter) Matches(event *model.Event) bool {
+	if filter.Domain != "" && filter.Domain != event.Domain {
+		return false
+	}
+
+	if filter.Task != "" && filter.Task != event.Task {
+		return false
+	}
+
+	if len(filter.Types) == 0 {
+		return true
+	}
+
+	for _, eventType := range filter.Types {
+		if eventType == event.Type {
+			return true
+		}
+	}
+
+	return false
+}
+
+//------------------------------------------------------------------------------
+
+// Subscription receives the events published on the event bus which match
+// its filter. Events are dropped if the subscriber is not able to keep up.
+type Subscription struct {
+	ID      string           // identifier of the subscription
+	Filter  EventFilter      // selection of events
+	Events  chan model.Event // buffered channel delivering the events
+	Dropped int              // number of events which have been dropped
+}
+
+//------------------------------------------------------------------------------
+
+// EventBus distributes the events handled by the engine to its subscribers.
+type EventBus struct {
+	sync.RWMutex
+	Subscriptions map[string]*Subscription
+}
+
+var eventBus *EventBus
+var eventBusOnce sync.Once
+
+//------------------------------------------------------------------------------
+
+// GetEventBus initialises and returns the event bus.
+func GetEventBus() *EventBus {
+
+	// initialise singleton once
+	eventBusOnce.Do(func() {
+		eventBus = &EventBus{Subscriptions: map[string]*Subscription{}}
+	})
+
+	return eventBus
+}
+
+//------------------------------------------------------------------------------
+
+// Subscribe registers a subscription for the events matching a filter which
+// buffers up to the given number of events.
+func (bus *EventBus) Subscribe(filter EventFilter, buffer int) *Subscription {
+	if buffer < 1 {
+		buffer = 1
+	}
+
+	subscription := &Subscription{
+		ID:     uuid.New().String(),
+		Filter: filter,
+		Events: make(chan model.Event, buffer),
+	}
+
+	bus.Lock()
+	defer bus.Unlock()
+
+	bus.Subscriptions[subscription.ID] = subscription
+
+	return subscription
+}
+
+//------------------------------------------------------------------------------
+
+// Unsubscribe removes a subscription and closes its channel.
+func (bus *EventBus) Unsubscribe(subscription *Subscription) {
+	bus.Lock()
+	defer bus.Unlock()
+
+	if _, found := bus.Subscriptions[subscription.ID]; !found {
+		return
+	}
+
+	delete(bus.Subscriptions, subscription.ID)
+	close(subscription.Events)
+}
+
+//------------------------------------------------------------------------------
+
+// Publish delivers an event to all matching subscriptions without blocking.
+func (bus *EventBus) Publish(event *model.Event) {
+	bus.Lock()
+	defer bus.Unlock()
+
+	for _, subscription := range bus.Subscriptions {
+		if !subscription.Filter.Matches(event) {
+			continue
+		}
+
+		select {
+		case subscription.Events <- *event:
+		default:
+			subscription.Dropped++
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// recordEvent saves an event in its domain, journals it and publishes it on
+// the event bus.
+func recordEvent(domain *model.Domain, event *model.Event) {
+	domain.AddEvent(event)
+	journalEvent(event)
+
+	GetEventBus().Publish(event)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"tsai.eu/orchestrator/hook"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// NewHookTask creates a new task which executes a hook of the template variant
+// of an instance before or after a transition
+func NewHookTask(domain string, parent string, name string, transition string, state string) (model.Task, error) {
+	var task model.Task
+
+	// TODO: check parameters if context exists
+	task.Type = "HookTask"
+	task.Domain = domain
+	task.Architecture = ""
+	task.Component = ""
+	task.Version = ""
+	task.Instance = ""
+	task.State = state
+	task.Transition = transition
+	task.Hook = name
+	task.UUID = uuid.New().String()
+	task.Parent = parent
+	task.Status = model.TaskStatusInitial
+	task.Phase = 0
+	task.Subtasks = []string{}
+
+	// add handlers
+	BindHandlers(&task)
+
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return task, errors.New("unknown domain")
+	}
+
+	// determine parent node
+	parentTask, err := d.GetTask(parent)
+	if err != nil {
+		return task, errors.New("unknown parent")
+	}
+
+	// add parent context
+	task.Architecture = parentTask.Architecture
+	task.Component = parentTask.Component
+	task.Version = parentTask.Version
+	task.Instance = parentTask.Instance
+
+	// add task to domain
+	err = d.AddTask(&task)
+	if err != nil {
+		return task, err
+	}
+
+	// success
+	return task, nil
+}
+
+//------------------------------------------------------------------------------
+
+// ExecuteHookTask runs the hook of the task. The failure of a hook which only
+// warns is recorded as error of the task which nevertheless completes.
+func ExecuteHookTask(task *model.Task) {
+	// get event channel
+	channel := GetEventChannel()
+
+	// check status
+	status := task.GetStatus()
+
+	if status != model.TaskStatusInitial {
+		return
+	}
+
+	// start the execution
+	startTask(task)
+
+	// run the hook
+	ctx, release := taskContext(task)
+	definition, err := executeHook(ctx, task)
+	cancelled := ctx.Err() != nil
+	release()
+
+	// a cancelled task is concluded by its termination or timeout
+	if cancelled {
+		return
+	}
+
+	if err != nil {
+		task.SetError(err)
+
+		if definition == nil || definition.OnFailure != model.HookWarn {
+			channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+			return
+		}
+	}
+
+	// signal completion
+	channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskCompletion, task.UUID)
+}
+
+//------------------------------------------------------------------------------
+
+// executeHook determines the hook of a task and runs it.
+func executeHook(ctx context.Context, task *model.Task) (*model.Hook, error) {
+	// collect relevant information
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		return nil, err
+	}
+	template, err := domain.GetTemplate(task.Component)
+	if err != nil {
+		return nil, err
+	}
+	variant, err := template.GetVariant(task.Version)
+	if err != nil {
+		return nil, err
+	}
+	definition, err := variant.GetHook(task.Hook)
+	if err != nil {
+		return nil, err
+	}
+	err = definition.Validate()
+	if err != nil {
+		return definition, err
+	}
+	runner, err := hook.GetRunner(definition.Type)
+	if err != nil {
+		return definition, err
+	}
+	configuration, err := model.GetConfiguration(domain.Name, task.Component, task.Instance)
+	if err != nil {
+		return definition, err
+	}
+	configuration.Context = ctx
+
+	// run the hook
+	return definition, runner.Run(definition, configuration)
+}
+
+//------------------------------------------------------------------------------
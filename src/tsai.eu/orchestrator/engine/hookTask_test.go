@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"testing"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+func TestHookTask(t *testing.T) {
+	tests := []struct {
+		name      string
+		command   string
+		onFailure string
+		expected  model.TaskStatus
+		fails     bool
+	}{
+		{"success", "true", model.HookAbort, model.TaskStatusCompleted, false},
+		{"abort", "false", model.HookAbort, model.TaskStatusFailed, true},
+		{"warn", "false", model.HookWarn, model.TaskStatusCompleted, true},
+	}
+
+	dispatcher := startTestDispatcher()
+	defer stopTestDispatcher(t, dispatcher)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{"app": {}})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			template, _ := domain.GetTemplate("app")
+			variant, _ := template.GetVariant("1.0.0")
+			hook, _ := model.NewHook("check", "pre-start", model.HookCommand)
+			hook.Command = []string{test.command}
+			hook.OnFailure = test.onFailure
+			variant.Hooks = append(variant.Hooks, hook)
+
+			instance := addTestInstance(t, domain, "app", model.InactiveState, "")
+
+			// the hook is executed on behalf of the instance of the parent task
+			parent := newTestTask(t, domain, "")
+			parent.Component = "app"
+			parent.Version = "1.0.0"
+			parent.Instance = instance.UUID
+
+			created, err := NewHookTask(domain.Name, parent.UUID, "check", "start", model.ActiveState)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			task := executeTestTask(t, dispatcher, domain, created.UUID)
+
+			if status := task.GetStatus(); status != test.expected {
+				t.Errorf("expected status %v, got %v", test.expected, status)
+			}
+			if failed := task.GetError() != ""; failed != test.fails {
+				t.Errorf("unexpected error '%s'", task.GetError())
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -30,11 +30,7 @@ func NewInstanceTask(domain string, parent string, architecture string, componen
 	task.Subtasks = []string{}
 
 	// add handlers
-	task.SetExecute(ExecuteInstanceTask)
-	task.SetTerminate(TerminateTask)
-	task.SetFailed(FailedTask)
-	task.SetTimeout(TimeoutTask)
-	task.SetCompleted(CompletedTask)
+	BindHandlers(&task)
 
 	// get domain
 	d, err := model.GetModel().GetDomain(domain)
@@ -54,7 +50,8 @@ func NewInstanceTask(domain string, parent string, architecture string, componen
 
 //------------------------------------------------------------------------------
 
-// ExecuteInstanceTask is the main task execution routine.
+// ExecuteInstanceTask is the main task execution routine: it plans the
+// transitions required to reach the desired state and executes them in sequence.
 func ExecuteInstanceTask(task *model.Task) {
 	// get event channel
 	channel := GetEventChannel()
@@ -66,74 +63,340 @@ func ExecuteInstanceTask(task *model.Task) {
 		return
 	}
 
-	// initialize if needed
+	// obtain exclusive access to the instance
+	acquired, err := GetLockManager().Acquire(task)
+	if err != nil {
+		startTask(task)
+		task.SetError(err)
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+		return
+	}
+
+	// wait until the lock is handed over
+	if !acquired {
+		return
+	}
+
+	// plan the transitions initially
 	if status == model.TaskStatusInitial {
-		// update status
-		task.Status = model.TaskStatusExecuting
+		// start the execution
+		startTask(task)
+
+		err := planInstanceTask(task)
+		if err != nil {
+			task.SetError(err)
+			channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+			return
+		}
+	}
+
+	// execute the transitions in sequence
+	ExecuteSequentialTask(task)
+
+	// instances which have been removed are no longer part of the component
+	// while all other instances have applied their current configuration
+	if task.GetStatus() == model.TaskStatusCompleted {
+		domain, _ := model.GetModel().GetDomain(task.Domain)
+		component, err := domain.GetComponent(task.Component)
+		if err == nil && task.State == model.GetStateMachine(component.Type).Initial {
+			component.DeleteInstance(task.Instance)
+		} else if err == nil {
+			recordFingerprint(domain, component, task.Instance)
+		}
 	}
+}
 
-	// TODO: implement and proper error handling
-	// - collect relevant information
-	// - determine current state and target state of instance and derive the required transition
-	// - trigger controller to execute transition
-	// - obtain endpoint
-	// - calculate endpoint information
-	// - trigger new execution
-	// - in case of error trigger failure
+//------------------------------------------------------------------------------
 
+// planInstanceTask determines the transitions required to move an instance from
+// its current state to the desired state and adds a subtask for each of them.
+func planInstanceTask(task *model.Task) error {
 	// collect relevant information
-	domain, _ := model.GetModel().GetDomain(task.Domain)
-	component, _ := domain.GetComponent(task.Component)
-	instance, _ := component.GetInstance(task.Instance)
-	controller, _ := ctrl.GetController(component.Type)
-	configuration, _ := model.GetConfiguration(domain.Name, component.Name, instance.UUID)
-
-	// determine current state and target state of instance and derive the required transition
-	currentState, _ := controller.Status(configuration)
-	targetState := task.State
-	transition, err := model.GetTransition(currentState.InstanceState, targetState)
-
-	// check for invalid states
+	domain, err := model.GetModel().GetDomain(task.Domain)
 	if err != nil {
-		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+		return err
+	}
+	err = provideComponent(domain, task.Component)
+	if err != nil {
+		return err
+	}
+	component, err := domain.GetComponent(task.Component)
+	if err != nil {
+		return err
+	}
+	instance, err := provideInstance(domain, component, task.Instance, task.Version)
+	if err != nil {
+		return err
+	}
+	controller, err := ctrl.GetController(component.Type)
+	if err != nil {
+		return err
+	}
+	configuration, err := model.GetConfiguration(domain.Name, component.Name, instance.UUID)
+	if err != nil {
+		return err
+	}
+
+	// determine current state of the instance
+	currentStatus, err := controller.Status(configuration)
+	if currentStatus == nil {
+		if err == nil {
+			err = errors.New("status of instance not reported by controller")
+		}
+		return err
+	}
+
+	// determine the required transitions
+	machine := model.GetStateMachine(component.Type)
+	transitions, err := machine.GetTransitions(currentStatus.InstanceState, task.State)
+	if err != nil {
+		return err
 	}
 
 	// check if reconfiguration is required
-	newDependencies := model.DetermineDependencies(domain, component, instance)
-	oldDependencies := instance.GetDependencies()
-
-	// execute the required transition
-	switch transition {
-	case "create":
-		instance.SetDependencies(newDependencies)
-		_, err = controller.Create(configuration)
-	case "start":
-		instance.SetDependencies(newDependencies)
-		_, err = controller.Start(configuration)
-	case "stop":
-		instance.SetDependencies(newDependencies)
-		_, err = controller.Stop(configuration)
-	case "destroy":
-		instance.SetDependencies(newDependencies)
-		_, err = controller.Destroy(configuration)
-	case "reset":
-		instance.SetDependencies(newDependencies)
-		_, err = controller.Reset(configuration)
-	case "configure":
-		instance.SetDependencies(newDependencies)
-		_, err = controller.Configure(configuration)
-	case "none":
-		if !util.AreEqual(oldDependencies, newDependencies) {
-			_, err = controller.Configure(configuration)
-		}
-	}
-
-	// check for errors
+	if currentStatus.InstanceState != machine.Initial && task.State != machine.Initial && reconfigurationReason(domain, component, instance) != "" {
+		transitions, err = reconfigurationTransitions(domain, component, instance, currentStatus.InstanceState, transitions)
+		if err != nil {
+			return err
+		}
+	}
+
+	// determine the hooks of the template variant
+	var variant *model.Variant
+	if template, err := domain.GetTemplate(component.Name); err == nil {
+		variant, _ = template.GetVariant(instance.Version)
+	}
+
+	// create a subtask for each transition surrounded by its hooks (an
+	// instance which has become operational needs to be ready before the post hooks)
+	state := currentStatus.InstanceState
+	for _, transition := range transitions {
+		err = addHookTasks(task, variant, model.HookPre, transition, state)
+		if err != nil {
+			return err
+		}
+
+		previous := state
+		state, _ = machine.GetTransitionResult(state, transition)
+
+		subtask, err := NewTransitionTask(task.Domain, task.UUID, transition, state)
+		if err != nil {
+			return err
+		}
+
+		task.AddSubtask(&subtask)
+
+		// wait until an instance which has become operational is ready
+		if machine.Running != "" && previous != machine.Running && state == machine.Running && determineProbe(domain, component, instance.Version, ProbeReadiness) != nil {
+			readiness, err := NewReadinessTask(task.Domain, task.UUID, state)
+			if err != nil {
+				return err
+			}
+
+			task.AddSubtask(&readiness)
+		}
+
+		err = addHookTasks(task, variant, model.HookPost, transition, state)
+		if err != nil {
+			return err
+		}
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// addHookTasks adds a subtask for each hook of a template variant which is
+// executed in a phase (pre/post) of a transition.
+func addHookTasks(task *model.Task, variant *model.Variant, phase string, transition string, state string) error {
+	if variant == nil {
+		return nil
+	}
+
+	for _, definition := range variant.GetHooks(phase, transition) {
+		subtask, err := NewHookTask(task.Domain, task.UUID, definition.Name, transition, state)
+		if err != nil {
+			return err
+		}
+
+		task.AddSubtask(&subtask)
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// reconfigurationReason determines why an instance needs to be reconfigured
+// ("" = no reconfiguration required). Instances which have not recorded the
+// fingerprint of their configuration yet are only reconfigured if the
+// endpoints of their dependencies have changed (see RecordBaselineFingerprints).
+func reconfigurationReason(domain *model.Domain, component *model.Component, instance *model.Instance) string {
+	if !util.AreEqual(instance.GetDependencies(), model.DetermineDependencies(domain, component, instance)) {
+		return "dependencies changed"
+	}
+
+	if instance.Fingerprint != "" && instance.Fingerprint != model.DetermineFingerprint(domain, component, instance) {
+		return "configuration changed"
+	}
+
+	return ""
+}
+
+//------------------------------------------------------------------------------
+
+// reconfigurationTransitions complements the transitions which move an
+// instance from its current state to its desired state so that they apply a
+// changed configuration according to the policy of its template variant:
+// either the instance is configured in place or it is destroyed and created
+// again. Transitions which already pass through the initial state or
+// configure the instance remain unchanged.
+func reconfigurationTransitions(domain *model.Domain, component *model.Component, instance *model.Instance, state string, transitions []string) ([]string, error) {
+	policy := model.ReconfigureConfigure
+	if template, err := domain.GetTemplate(component.Name); err == nil {
+		if variant, err := template.GetVariant(instance.Version); err == nil {
+			policy, err = variant.GetReconfigure()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// determine the states passed by the transitions
+	machine := model.GetStateMachine(component.Type)
+
+	states := []string{state}
+	for _, transition := range transitions {
+		if transition == "configure" {
+			return transitions, nil
+		}
+
+		next, err := machine.GetTransitionResult(states[len(states)-1], transition)
+		if err != nil {
+			return nil, err
+		}
+		if next == machine.Initial {
+			return transitions, nil
+		}
+
+		states = append(states, next)
+	}
+
+	// configure the instance in the first state which permits it
+	if policy == model.ReconfigureConfigure {
+		for index, current := range states {
+			if _, err := machine.GetTransition(current, "configure"); err != nil {
+				continue
+			}
+
+			result := append([]string{}, transitions[:index]...)
+			result = append(result, "configure")
+			return append(result, transitions[index:]...), nil
+		}
+
+		return nil, errors.New("instance can not be configured in state: " + state)
+	}
+
+	// replace the instance by passing through the initial state
+	removal, err := machine.GetTransitions(state, machine.Initial)
 	if err != nil {
-		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
-	} else {
-		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskCompletion, task.UUID)
+		return nil, err
+	}
+
+	creation, err := machine.GetTransitions(machine.Initial, states[len(states)-1])
+	if err != nil {
+		return nil, err
 	}
+
+	return append(removal, creation...), nil
+}
+
+//------------------------------------------------------------------------------
+
+// recordFingerprint records the fingerprint of the configuration which has
+// been applied to an instance.
+func recordFingerprint(domain *model.Domain, component *model.Component, uuid string) {
+	instance, err := component.GetInstance(uuid)
+	if err != nil {
+		return
+	}
+
+	fingerprint := model.DetermineFingerprint(domain, component, instance)
+	if fingerprint == instance.Fingerprint {
+		return
+	}
+
+	instance.Fingerprint = fingerprint
+	journalInstance(domain.Name, component, instance)
+}
+
+//------------------------------------------------------------------------------
+
+// RecordBaselineFingerprints records the fingerprint of the current
+// configuration of all existing instances of a model which have not recorded
+// one yet, e.g. instances loaded from a model file or restored from a journal
+// written before fingerprints have been introduced. Later changes of their
+// configuration are thereby detected.
+func RecordBaselineFingerprints(m *model.Model) {
+	domains, _ := m.ListDomains()
+	for _, name := range domains {
+		domain, err := m.GetDomain(name)
+		if err != nil {
+			continue
+		}
+
+		components, _ := domain.ListComponents()
+		for _, componentName := range components {
+			component, err := domain.GetComponent(componentName)
+			if err != nil {
+				continue
+			}
+
+			uuids, _ := component.ListInstances()
+			for _, uuid := range uuids {
+				instance, err := component.GetInstance(uuid)
+				if err != nil || instance.Fingerprint != "" || instance.State == model.GetStateMachine(component.Type).Initial {
+					continue
+				}
+
+				recordFingerprint(domain, component, uuid)
+			}
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// provideInstance retrieves an instance of a component and creates it if it does not exist yet.
+func provideInstance(domain *model.Domain, component *model.Component, uuid string, version string) (*model.Instance, error) {
+	if component == nil {
+		return nil, errors.New("unknown component")
+	}
+
+	// check if the instance already exists
+	instance, err := component.GetInstance(uuid)
+	if err == nil {
+		return instance, nil
+	}
+
+	// create a new instance in its initial state
+	instance, _ = model.NewInstance(version)
+	instance.UUID = uuid
+	instance.State = model.GetStateMachine(component.Type).Initial
+
+	// the dependencies of the instance determine where it is located
+	instance.SetDependencies(model.DetermineDependencies(domain, component, instance))
+
+	err = component.AddInstance(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	// success
+	return instance, nil
 }
 
 //------------------------------------------------------------------------------
@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+func TestReconfigurationReason(t *testing.T) {
+	tests := []struct {
+		name          string
+		fingerprint   bool
+		configuration bool // configuration of the variant changes
+		endpoint      bool // endpoint of the dependency changes
+		expected      string
+	}{
+		{"unchanged", true, false, false, ""},
+		{"changed configuration", true, true, false, "configuration changed"},
+		{"changed configuration without fingerprint", false, true, false, ""},
+		{"changed dependencies", true, false, true, "dependencies changed"},
+		{"changed dependencies without fingerprint", false, false, true, "dependencies changed"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{"app": {"db"}, "db": {}})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			addTestInstance(t, domain, "db", model.ActiveState, "db-1")
+			instance := addTestInstance(t, domain, "app", model.ActiveState, "app-1")
+
+			component, _ := domain.GetComponent("app")
+			if test.fingerprint {
+				instance.Fingerprint = model.DetermineFingerprint(domain, component, instance)
+			}
+
+			if test.configuration {
+				template, _ := domain.GetTemplate("app")
+				variant, _ := template.GetVariant("1.0.0")
+				variant.Configuration = "changed"
+			}
+			if test.endpoint {
+				dependency, _ := domain.GetComponent("db")
+				dependency.AddEndpoint("1.0.0", "db-2")
+			}
+
+			if reason := reconfigurationReason(domain, component, instance); reason != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, reason)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestReconfigurationTransitions(t *testing.T) {
+	// component type which can not be configured
+	machine, _ := model.NewStateMachine("unconfigurable", "initial", "failure")
+	machine.AddState("running")
+	machine.AddTransition("create", "initial", "running", "", "")
+	machine.AddTransition("destroy", "running", "initial", "", "")
+	if err := model.RegisterStateMachine("unconfigurable", machine); err != nil {
+		t.Fatalf("unable to register state machine: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		ctype       string
+		policy      string
+		state       string
+		transitions []string
+		expected    []string
+		fails       bool
+	}{
+		{"configure in place", "test", model.ReconfigureConfigure, "active", []string{}, []string{"configure"}, false},
+		{"configure before start", "test", model.ReconfigureConfigure, "inactive", []string{"start"}, []string{"configure", "start"}, false},
+		{"configure before stop", "test", model.ReconfigureConfigure, "active", []string{"stop"}, []string{"configure", "stop"}, false},
+		{"default policy", "test", "", "active", []string{}, []string{"configure"}, false},
+		{"configuring transitions", "test", model.ReconfigureConfigure, "inactive", []string{"configure", "start"}, []string{"configure", "start"}, false},
+		{"removal", "test", model.ReconfigureConfigure, "active", []string{"stop", "destroy"}, []string{"stop", "destroy"}, false},
+		{"replace", "test", model.ReconfigureReplace, "active", []string{}, []string{"stop", "destroy", "create", "configure", "start"}, false},
+		{"replace before start", "test", model.ReconfigureReplace, "inactive", []string{"start"}, []string{"destroy", "create", "configure", "start"}, false},
+		{"replace during removal", "test", model.ReconfigureReplace, "active", []string{"stop", "destroy"}, []string{"stop", "destroy"}, false},
+		{"replace without configure", "unconfigurable", model.ReconfigureReplace, "running", []string{}, []string{"destroy", "create"}, false},
+		{"configure without configure", "unconfigurable", model.ReconfigureConfigure, "running", []string{}, nil, true},
+		{"invalid transition", "test", model.ReconfigureConfigure, "active", []string{"start"}, nil, true},
+		{"invalid policy", "test", "unknown", "active", []string{}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{"app": {}})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			template, _ := domain.GetTemplate("app")
+			template.Type = test.ctype
+			variant, _ := template.GetVariant("1.0.0")
+			variant.Reconfigure = test.policy
+
+			component, _ := model.NewComponent("app", test.ctype)
+			domain.AddComponent(component)
+			instance := addTestInstance(t, domain, "app", test.state, "")
+
+			transitions, err := reconfigurationTransitions(domain, component, instance, test.state, test.transitions)
+			if test.fails {
+				if err == nil {
+					t.Errorf("expected an error, got %v", transitions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(transitions, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, transitions)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,629 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// JournalRecord is a single entry of the journal: either an event, a snapshot
+// of a task, a snapshot of an instance or an applied architecture.
+type JournalRecord struct {
+	Time         int64                `json:"time"`                   // time of the record (nsecs since 1.1.1970)
+	Event        *model.Event         `json:"event,omitempty"`        // event which has been dispatched
+	Task         *model.Task          `json:"task,omitempty"`         // snapshot of a task
+	Instance     *JournalInstance     `json:"instance,omitempty"`     // snapshot of an instance
+	Architecture *JournalArchitecture `json:"architecture,omitempty"` // copy of an applied architecture
+}
+
+// JournalInstance captures the state of an instance and of its component. A
+// record without an instance only captures the state of the component.
+type JournalInstance struct {
+	Domain    string                    `json:"domain"`             // domain of the component
+	Component string                    `json:"component"`          // name of the component
+	Type      string                    `json:"type"`               // type of the component
+	Endpoint  string                    `json:"endpoint"`           // endpoint of the component
+	Endpoints map[string]string         `json:"endpoints"`          // endpoints of the component versions
+	Active    string                    `json:"active,omitempty"`   // version the endpoint of the component refers to
+	Previous  string                    `json:"previous,omitempty"` // version kept for a rollback of a blue/green release
+	Canary    string                    `json:"canary,omitempty"`   // version of a canary release
+	Weights   []*model.WeightedEndpoint `json:"weights,omitempty"`  // shares of the traffic during a canary release
+	Instance  *model.Instance           `json:"instance,omitempty"` // snapshot of the instance
+}
+
+// JournalArchitecture captures the architecture an architecture task applies or
+// the architecture which has been applied successfully last.
+type JournalArchitecture struct {
+	Domain       string              `json:"domain"`                 // domain of the architecture
+	Task         string              `json:"task,omitempty"`         // architecture task ("" = applied architecture of the domain)
+	Architecture *model.Architecture `json:"architecture,omitempty"` // copy of the architecture (nil = task has been concluded)
+}
+
+// journalLimit defines the number of records after which the journal is
+// compacted into a checkpoint of the current state.
+const journalLimit = 10000
+
+// Journal is an append-only file of events and snapshots of tasks and instances.
+type Journal struct {
+	sync.Mutex
+	Filename  string          // name of the journal file
+	File      *os.File        // file of the journal
+	Records   int             // number of records since the last checkpoint
+	Journaled map[string]bool // tasks which have been recorded at least once
+}
+
+var journal *Journal
+
+//------------------------------------------------------------------------------
+
+// OpenJournal opens a journal file to which all subsequent events and changes
+// of tasks are appended.
+func OpenJournal(filename string) error {
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, "unable to open journal")
+	}
+
+	journal = &Journal{
+		Filename:  filename,
+		File:      file,
+		Journaled: map[string]bool{},
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// CloseJournal closes the journal file.
+func CloseJournal() error {
+	if journal == nil {
+		return nil
+	}
+
+	err := journal.File.Close()
+	journal = nil
+
+	return err
+}
+
+//------------------------------------------------------------------------------
+
+// write appends a record to the journal and flushes it to disk.
+func (j *Journal) write(record JournalRecord) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+
+	j.File.Write(append(data, '\n'))
+	j.File.Sync()
+
+	j.Records++
+}
+
+//------------------------------------------------------------------------------
+
+// CompactJournal replaces the records of the journal by a checkpoint of the
+// current state of the model.
+func CompactJournal() error {
+	if journal == nil {
+		return nil
+	}
+
+	journal.Lock()
+	defer journal.Unlock()
+
+	return journal.checkpoint()
+}
+
+//------------------------------------------------------------------------------
+
+// checkpoint writes the events, tasks, components and instances of the model
+// to a new journal file which then replaces the current journal.
+func (j *Journal) checkpoint() error {
+	filename := j.Filename + ".checkpoint"
+
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, "unable to create checkpoint")
+	}
+
+	// write the checkpoint to the new file
+	current := j.File
+	j.File = file
+	j.Journaled = map[string]bool{}
+
+	m := model.GetModel()
+	domains, _ := m.ListDomains()
+	for _, name := range domains {
+		domain, err := m.GetDomain(name)
+		if err != nil {
+			continue
+		}
+
+		// events in the order of their occurrence
+		events := []*model.Event{}
+		uuids, _ := domain.ListEvents()
+		for _, uuid := range uuids {
+			if event, err := domain.GetEvent(uuid); err == nil {
+				events = append(events, event)
+			}
+		}
+		sort.Slice(events, func(i, k int) bool { return events[i].Time < events[k].Time })
+
+		for _, event := range events {
+			j.write(JournalRecord{Time: event.Time, Event: event})
+		}
+
+		// tasks and the architectures they apply
+		uuids, _ = domain.ListTasks()
+		for _, uuid := range uuids {
+			task, err := domain.GetTask(uuid)
+			if err == nil && !j.Journaled[uuid] {
+				j.recordTask(domain, task)
+			}
+
+			appliedArchitecturesLock.Lock()
+			architecture, found := appliedArchitectures[uuid]
+			appliedArchitecturesLock.Unlock()
+
+			if found {
+				j.write(JournalRecord{Time: time.Now().UnixNano(), Architecture: &JournalArchitecture{Domain: domain.Name, Task: uuid, Architecture: architecture}})
+			}
+		}
+
+		// applied architecture
+		if domain.Applied != nil {
+			j.write(JournalRecord{Time: time.Now().UnixNano(), Architecture: &JournalArchitecture{Domain: domain.Name, Architecture: domain.Applied}})
+		}
+
+		// components and their instances
+		components, _ := domain.ListComponents()
+		for _, cname := range components {
+			component, err := domain.GetComponent(cname)
+			if err != nil {
+				continue
+			}
+
+			j.write(JournalRecord{Time: time.Now().UnixNano(), Instance: componentRecord(domain.Name, component)})
+
+			instances, _ := component.ListInstances()
+			for _, uuid := range instances {
+				if instance, err := component.GetInstance(uuid); err == nil {
+					record := componentRecord(domain.Name, component)
+					record.Instance = snapshotInstance(instance)
+
+					j.write(JournalRecord{Time: time.Now().UnixNano(), Instance: record})
+				}
+			}
+		}
+	}
+
+	// replace the journal by the checkpoint
+	err = os.Rename(filename, j.Filename)
+	if err != nil {
+		j.File = current
+		file.Close()
+		os.Remove(filename)
+		return errors.Wrap(err, "unable to replace journal")
+	}
+
+	current.Close()
+	j.Records = 0
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// journalEvent records an event in the journal.
+func journalEvent(event *model.Event) {
+	if journal == nil {
+		return
+	}
+
+	journal.Lock()
+	defer journal.Unlock()
+
+	journal.write(JournalRecord{Time: time.Now().UnixNano(), Event: event})
+
+	// compact the journal once it has grown beyond its limit
+	if journal.Records > journalLimit {
+		journal.checkpoint()
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// journalTask records a snapshot of a task and of all of its subtasks which
+// have not been recorded yet.
+func journalTask(task *model.Task) {
+	if journal == nil {
+		return
+	}
+
+	journal.Lock()
+	defer journal.Unlock()
+
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		return
+	}
+
+	journal.recordTask(domain, task)
+}
+
+//------------------------------------------------------------------------------
+
+// journalNewTask records a snapshot of a task which has not been recorded yet.
+// It is called before the task is executed for the first time so that the
+// snapshots of its parent never capture the task while it is running.
+func journalNewTask(task *model.Task) {
+	if journal == nil {
+		return
+	}
+
+	journal.Lock()
+	defer journal.Unlock()
+
+	if journal.Journaled[task.UUID] {
+		return
+	}
+
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		return
+	}
+
+	journal.recordTask(domain, task)
+}
+
+//------------------------------------------------------------------------------
+
+// recordTask writes snapshots of a task and of all of its subtasks which have
+// not been recorded yet. The snapshots are taken while the tasks are locked
+// since the tasks may be executing.
+func (j *Journal) recordTask(domain *model.Domain, task *model.Task) {
+	snapshot := task.Snapshot()
+
+	// determine new subtasks
+	subtasks := []*model.Task{}
+
+	pending := append([]string{}, snapshot.Subtasks...)
+	for len(pending) > 0 {
+		uuid := pending[0]
+		pending = pending[1:]
+
+		if j.Journaled[uuid] {
+			continue
+		}
+
+		subtask, err := domain.GetTask(uuid)
+		if err != nil {
+			continue
+		}
+
+		subtask = subtask.Snapshot()
+		subtasks = append(subtasks, subtask)
+		pending = append(pending, subtask.Subtasks...)
+	}
+
+	// record new subtasks before their parents so that a replayed task never
+	// refers to a subtask which is unknown
+	for index := len(subtasks) - 1; index >= 0; index-- {
+		j.Journaled[subtasks[index].UUID] = true
+		j.write(JournalRecord{Time: time.Now().UnixNano(), Task: subtasks[index]})
+	}
+
+	// record the task
+	j.Journaled[snapshot.UUID] = true
+	j.write(JournalRecord{Time: time.Now().UnixNano(), Task: snapshot})
+}
+
+//------------------------------------------------------------------------------
+
+// journalInstance records a snapshot of an instance and of its component in
+// the journal.
+func journalInstance(domain string, component *model.Component, instance *model.Instance) {
+	if journal == nil {
+		return
+	}
+
+	record := componentRecord(domain, component)
+	record.Instance = snapshotInstance(instance)
+
+	journal.Lock()
+	defer journal.Unlock()
+
+	journal.write(JournalRecord{Time: time.Now().UnixNano(), Instance: record})
+}
+
+//------------------------------------------------------------------------------
+
+// journalComponent records a snapshot of a component in the journal.
+func journalComponent(domain string, component *model.Component) {
+	if journal == nil {
+		return
+	}
+
+	record := componentRecord(domain, component)
+
+	journal.Lock()
+	defer journal.Unlock()
+
+	journal.write(JournalRecord{Time: time.Now().UnixNano(), Instance: record})
+}
+
+//------------------------------------------------------------------------------
+
+// journalArchitecture records the architecture an architecture task applies
+// or the applied architecture of a domain in the journal.
+func journalArchitecture(domain string, task string, architecture *model.Architecture) {
+	if journal == nil {
+		return
+	}
+
+	journal.Lock()
+	defer journal.Unlock()
+
+	journal.write(JournalRecord{
+		Time:         time.Now().UnixNano(),
+		Architecture: &JournalArchitecture{Domain: domain, Task: task, Architecture: architecture},
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// componentRecord captures the endpoints and the release of a component.
+func componentRecord(domain string, component *model.Component) *JournalInstance {
+	weights := []*model.WeightedEndpoint{}
+	for _, weight := range component.Weights {
+		copied := *weight
+		weights = append(weights, &copied)
+	}
+
+	return &JournalInstance{
+		Domain:    domain,
+		Component: component.Name,
+		Type:      component.Type,
+		Endpoint:  component.Endpoint,
+		Endpoints: component.GetEndpoints(),
+		Active:    component.Active,
+		Previous:  component.Previous,
+		Canary:    component.Canary,
+		Weights:   weights,
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// snapshotInstance copies an instance.
+func snapshotInstance(instance *model.Instance) *model.Instance {
+	snapshot, _ := model.NewInstance(instance.Version)
+	snapshot.UUID = instance.UUID
+	snapshot.State = instance.State
+	snapshot.Endpoint = instance.Endpoint
+	snapshot.Fingerprint = instance.Fingerprint
+
+	instance.Dependencies.RLock()
+	for name, endpoint := range instance.Dependencies.Map {
+		snapshot.Dependencies.Map[name] = endpoint
+	}
+	instance.Dependencies.RUnlock()
+
+	return snapshot
+}
+
+//------------------------------------------------------------------------------
+
+// ReplayJournal restores the events and the latest snapshots of the tasks and
+// instances recorded in a journal file. Incomplete records are ignored.
+func ReplayJournal(m *model.Model, filename string) error {
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to open journal")
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		var record JournalRecord
+
+		// ignore records which have not been written completely
+		if json.Unmarshal(scanner.Bytes(), &record) != nil {
+			continue
+		}
+
+		// restore event
+		if record.Event != nil {
+			domain, err := m.GetDomain(record.Event.Domain)
+			if err == nil {
+				domain.AddEvent(record.Event)
+			}
+		}
+
+		// restore task
+		if record.Task != nil {
+			domain, err := m.GetDomain(record.Task.Domain)
+			if err != nil {
+				continue
+			}
+
+			task, err := domain.GetTask(record.Task.UUID)
+			if err != nil {
+				domain.AddTask(record.Task)
+			} else {
+				*task = *record.Task
+			}
+		}
+
+		// restore instance
+		if record.Instance != nil {
+			replayInstance(m, record.Instance)
+		}
+
+		// restore architecture
+		if record.Architecture != nil {
+			replayArchitecture(m, record.Architecture)
+		}
+	}
+
+	// success
+	return scanner.Err()
+}
+
+//------------------------------------------------------------------------------
+
+// replayInstance restores the snapshot of an instance and of its component.
+func replayInstance(m *model.Model, record *JournalInstance) {
+	domain, err := m.GetDomain(record.Domain)
+	if err != nil {
+		return
+	}
+
+	// restore component
+	component, err := domain.GetComponent(record.Component)
+	if err != nil {
+		component, _ = model.NewComponent(record.Component, record.Type)
+		domain.AddComponent(component)
+	}
+	component.Endpoint = record.Endpoint
+	component.Active = record.Active
+	component.Previous = record.Previous
+	component.Canary = record.Canary
+	component.Weights = record.Weights
+
+	// records without endpoints have been written before the endpoints of the
+	// versions were journaled
+	if record.Endpoints != nil {
+		versions, _ := component.ListEndpoints()
+		for _, version := range versions {
+			if _, found := record.Endpoints[version]; !found {
+				component.DeleteEndpoint(version)
+			}
+		}
+		for version, endpoint := range record.Endpoints {
+			component.AddEndpoint(version, endpoint)
+		}
+	}
+
+	if record.Instance == nil {
+		return
+	}
+
+	// instances which have been removed are no longer part of the component
+	component.DeleteInstance(record.Instance.UUID)
+	if record.Instance.State != model.GetStateMachine(component.Type).Initial {
+		component.AddInstance(record.Instance)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// replayArchitecture restores the architecture an architecture task applies or
+// the applied architecture of a domain.
+func replayArchitecture(m *model.Model, record *JournalArchitecture) {
+	domain, err := m.GetDomain(record.Domain)
+	if err != nil {
+		return
+	}
+
+	if record.Task == "" {
+		domain.Applied = record.Architecture
+		return
+	}
+
+	appliedArchitecturesLock.Lock()
+	defer appliedArchitecturesLock.Unlock()
+
+	if record.Architecture == nil {
+		delete(appliedArchitectures, record.Task)
+	} else {
+		appliedArchitectures[record.Task] = record.Architecture
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// ResumeTasks defines the event handlers of all tasks of a model and resumes
+// the execution of all tasks which have been executing. Interrupted transitions
+// and readiness checks are executed again, interrupted hooks fail since they
+// can not safely be repeated.
+func ResumeTasks(m *model.Model) {
+	channel := GetEventChannel()
+	events := []model.Event{}
+
+	domains, _ := m.ListDomains()
+	for _, name := range domains {
+		domain, _ := m.GetDomain(name)
+
+		tasks, _ := domain.ListTasks()
+		for _, uuid := range tasks {
+			task, _ := domain.GetTask(uuid)
+
+			// restore handlers
+			if BindHandlers(task) != nil {
+				continue
+			}
+
+			if task.GetStatus() != model.TaskStatusExecuting {
+				continue
+			}
+
+			// continue to watch the deadline
+			trackDeadline(task)
+
+			switch task.Type {
+			case "TransitionTask", "ReadinessTask":
+				// execute again
+				task.SetStatus(model.TaskStatusInitial)
+				events = append(events, model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskExecution, task.Parent))
+				continue
+			case "HookTask":
+				// fail
+				task.SetError(errors.New("hook interrupted by a restart"))
+				events = append(events, model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID))
+				continue
+			case "ApprovalTask":
+				// the deadlines of the parents remain suspended
+				suspendDeadlines(task)
+			}
+
+			// resume execution
+			events = append(events, model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskExecution, ""))
+
+			// start subtasks which may not have been triggered yet
+			if task.Type == "ParallelTask" || task.Type == "ServiceTask" {
+				for _, suuid := range task.GetSubtasks() {
+					subtask, err := domain.GetTask(suuid)
+					if err == nil && subtask.GetStatus() == model.TaskStatusInitial {
+						events = append(events, model.NewEvent(task.Domain, suuid, model.EventTypeTaskExecution, task.UUID))
+					}
+				}
+			}
+		}
+	}
+
+	// trigger the tasks
+	for _, event := range events {
+		channel <- event
+	}
+}
+
+//------------------------------------------------------------------------------
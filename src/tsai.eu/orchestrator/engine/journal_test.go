@@ -0,0 +1,261 @@
+package engine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// openTestJournal opens a journal within a temporary directory and returns the
+// name of the journal file together with the directory.
+func openTestJournal(t *testing.T) (string, string) {
+	directory, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatalf("unable to create directory: %v", err)
+	}
+
+	filename := filepath.Join(directory, "journal.log")
+	if err := OpenJournal(filename); err != nil {
+		os.RemoveAll(directory)
+		t.Fatalf("unable to open journal: %v", err)
+	}
+
+	return filename, directory
+}
+
+//------------------------------------------------------------------------------
+
+// replayTestJournal closes the journal and replays it into an empty model
+// with a domain of the given name.
+func replayTestJournal(t *testing.T, filename string, name string) *model.Domain {
+	CloseJournal()
+
+	m, _ := model.NewModel()
+	domain, _ := model.NewDomain(name)
+	m.AddDomain(domain)
+
+	if err := ReplayJournal(m, filename); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return domain
+}
+
+//------------------------------------------------------------------------------
+
+func TestReplayTask(t *testing.T) {
+	domain := newTestDomain(t, map[string][]string{})
+	defer model.GetModel().DeleteDomain(domain.Name)
+
+	filename, directory := openTestJournal(t)
+	defer os.RemoveAll(directory)
+
+	task := newTestTask(t, domain, "")
+	subtask := newTestTask(t, domain, task.UUID)
+	task.AddSubtask(subtask)
+	journalTask(task)
+	task.SetStatus(model.TaskStatusCompleted)
+	journalTask(task)
+
+	replayed := replayTestJournal(t, filename, domain.Name)
+
+	restored, err := replayed.GetTask(task.UUID)
+	if err != nil {
+		t.Fatalf("task has not been restored")
+	}
+	if restored.Status != model.TaskStatusCompleted {
+		t.Errorf("expected status %v, got %v", model.TaskStatusCompleted, restored.Status)
+	}
+	if len(restored.Subtasks) != 1 || restored.Subtasks[0] != subtask.UUID {
+		t.Fatalf("expected subtask %s, got %v", subtask.UUID, restored.Subtasks)
+	}
+	if _, err := replayed.GetTask(subtask.UUID); err != nil {
+		t.Errorf("subtask has not been restored")
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestReplayInstance(t *testing.T) {
+	domain := newTestDomain(t, map[string][]string{"app": {}})
+	defer model.GetModel().DeleteDomain(domain.Name)
+
+	filename, directory := openTestJournal(t)
+	defer os.RemoveAll(directory)
+
+	instance := addTestInstance(t, domain, "app", model.ActiveState, "app-1")
+	removed := addTestInstance(t, domain, "app", model.ActiveState, "app-1")
+
+	component, _ := domain.GetComponent("app")
+	component.AddEndpoint("2.0.0", "app-2")
+	component.Endpoint = "app-1"
+	component.Active = "1.0.0"
+	component.Canary = "2.0.0"
+	component.Weights = []*model.WeightedEndpoint{{Version: "1.0.0", Endpoint: "app-1", Weight: 90}, {Version: "2.0.0", Endpoint: "app-2", Weight: 10}}
+
+	journalInstance(domain.Name, component, instance)
+	journalInstance(domain.Name, component, removed)
+	instance.State = model.InactiveState
+	journalInstance(domain.Name, component, instance)
+	removed.State = model.InitialState
+	journalInstance(domain.Name, component, removed)
+
+	replayed := replayTestJournal(t, filename, domain.Name)
+
+	restored, err := replayed.GetComponent("app")
+	if err != nil {
+		t.Fatalf("component has not been restored")
+	}
+	if restored.Type != "test" || restored.Endpoint != "app-1" {
+		t.Errorf("unexpected component type %s or endpoint %s", restored.Type, restored.Endpoint)
+	}
+	for version, expected := range map[string]string{"1.0.0": "app-1", "2.0.0": "app-2"} {
+		if endpoint, _ := restored.GetEndpoint(version); endpoint != expected {
+			t.Errorf("expected endpoint %s of version %s, got %s", expected, version, endpoint)
+		}
+	}
+	if restored.Active != "1.0.0" || restored.Canary != "2.0.0" || len(restored.Weights) != 2 || restored.Weights[1].Weight != 10 {
+		t.Errorf("release state has not been restored")
+	}
+
+	restoredInstance, err := restored.GetInstance(instance.UUID)
+	if err != nil {
+		t.Fatalf("instance has not been restored")
+	}
+	if restoredInstance.State != model.InactiveState {
+		t.Errorf("expected state %s, got %s", model.InactiveState, restoredInstance.State)
+	}
+	if _, err := restored.GetInstance(removed.UUID); err == nil {
+		t.Errorf("removed instance has been restored")
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestReplayEvent(t *testing.T) {
+	domain := newTestDomain(t, map[string][]string{})
+	defer model.GetModel().DeleteDomain(domain.Name)
+
+	filename, directory := openTestJournal(t)
+	defer os.RemoveAll(directory)
+
+	event := model.NewEvent(domain.Name, "", model.EventTypeTaskExecution, "")
+	journalEvent(&event)
+
+	// a record interrupted by a crash is skipped
+	task := newTestTask(t, domain, "")
+	journalTask(task)
+	journal.File.WriteString(`{"time":1,"task":{"domain":"`)
+
+	replayed := replayTestJournal(t, filename, domain.Name)
+
+	if _, err := replayed.GetEvent(event.UUID); err != nil {
+		t.Errorf("event has not been restored")
+	}
+	if _, err := replayed.GetTask(task.UUID); err != nil {
+		t.Errorf("task has not been restored")
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestReplayJournalWithoutJournal(t *testing.T) {
+	m, _ := model.NewModel()
+
+	if err := ReplayJournal(m, filepath.Join(os.TempDir(), "missing", "journal.log")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestPlanAfterReplay(t *testing.T) {
+	domain := newTestDomain(t, map[string][]string{"app": {"db"}, "db": {}})
+	defer model.GetModel().DeleteDomain(domain.Name)
+
+	filename, directory := openTestJournal(t)
+	defer os.RemoveAll(directory)
+
+	architecture := addTestArchitecture(t, domain, "architecture",
+		testSetup{"db", "1.0.0", "active", 1},
+		testSetup{"app", "1.0.0", "active", 2})
+
+	for _, name := range []string{"db", "app", "app"} {
+		instance := addTestInstance(t, domain, name, model.ActiveState, name+"-1")
+		component, _ := domain.GetComponent(name)
+		instance.Fingerprint = model.DetermineFingerprint(domain, component, instance)
+		journalInstance(domain.Name, component, instance)
+	}
+	CloseJournal()
+
+	// replay the components into the domain which still provides the templates
+	for _, name := range []string{"db", "app"} {
+		domain.DeleteComponent(name)
+	}
+	if err := ReplayJournal(model.GetModel(), filename); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plan, err := NewPlan(domain, architecture)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Changes() != 0 {
+		t.Errorf("expected no changes after the replay, got %d", plan.Changes())
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestCompactJournal(t *testing.T) {
+	domain := newTestDomain(t, map[string][]string{"app": {}})
+	defer model.GetModel().DeleteDomain(domain.Name)
+
+	filename, directory := openTestJournal(t)
+	defer os.RemoveAll(directory)
+
+	task := newTestTask(t, domain, "")
+	instance := addTestInstance(t, domain, "app", model.ActiveState, "app-1")
+	component, _ := domain.GetComponent("app")
+
+	for _, status := range []model.TaskStatus{model.TaskStatusExecuting, model.TaskStatusCompleted} {
+		task.SetStatus(status)
+		journalTask(task)
+		journalInstance(domain.Name, component, instance)
+	}
+
+	if err := CompactJournal(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, _ := ioutil.ReadFile(filename)
+	if records := strings.Count(string(data), `"task":{`); records != 1 {
+		t.Errorf("expected a single snapshot of the task, got %d", records)
+	}
+
+	// records after the checkpoint are appended to the compacted journal
+	instance.State = model.InactiveState
+	journalInstance(domain.Name, component, instance)
+
+	replayed := replayTestJournal(t, filename, domain.Name)
+
+	restored, err := replayed.GetTask(task.UUID)
+	if err != nil || restored.Status != model.TaskStatusCompleted {
+		t.Errorf("completed task has not been restored")
+	}
+	restoredComponent, _ := replayed.GetComponent("app")
+	if restoredComponent == nil {
+		t.Fatalf("component has not been restored")
+	}
+	if restoredInstance, err := restoredComponent.GetInstance(instance.UUID); err != nil || restoredInstance.State != model.InactiveState {
+		t.Errorf("instance has not been restored")
+	}
+}
+
+//------------------------------------------------------------------------------
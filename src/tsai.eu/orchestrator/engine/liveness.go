@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// StartLivenessMonitor periodically checks the liveness of all running instances of the model.
+func StartLivenessMonitor(m *model.Model, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			domains, _ := m.ListDomains()
+			for _, name := range domains {
+				domain, err := m.GetDomain(name)
+				if err != nil {
+					continue
+				}
+
+				CheckLiveness(domain)
+			}
+		}
+	}()
+}
+
+//------------------------------------------------------------------------------
+
+// CheckLiveness verifies that the running instances of a domain are alive.
+// Instances which fail their liveness probe are marked as failed and an event
+// describing the failure is recorded.
+func CheckLiveness(domain *model.Domain) []*model.Event {
+	events := []*model.Event{}
+
+	components, _ := domain.ListComponents()
+	sort.Strings(components)
+	for _, name := range components {
+		component, _ := domain.GetComponent(name)
+		machine := model.GetStateMachine(component.Type)
+
+		instances, _ := component.ListInstances()
+		sort.Strings(instances)
+		for _, uuid := range instances {
+			instance, err := component.GetInstance(uuid)
+			if err != nil || machine.Running == "" || instance.State != machine.Running {
+				continue
+			}
+
+			// instances which are being changed by a task are skipped
+			if !tryLock(domain.Name, name, uuid, "liveness") {
+				continue
+			}
+
+			err = checkLiveness(domain, component, instance, machine)
+			if err != nil {
+				// mark the instance as failed
+				model.SetInstanceState(instance, machine.Failure)
+				journalInstance(domain.Name, component, instance)
+			}
+
+			unlock(domain.Name, name, uuid, "liveness")
+
+			if err == nil {
+				continue
+			}
+
+			event := model.NewEvent(domain.Name, "", model.EventTypeLiveness, "liveness")
+			event.Detail = fmt.Sprintf("instance '%s' of component '%s' has failed its liveness check: %s", uuid, name, err)
+
+			recordEvent(domain, &event)
+
+			events = append(events, &event)
+		}
+	}
+
+	return events
+}
+
+//------------------------------------------------------------------------------
+
+// checkLiveness runs the liveness probe of an instance which is still running
+// if a probe has been defined.
+func checkLiveness(domain *model.Domain, component *model.Component, instance *model.Instance, machine *model.StateMachine) error {
+	if instance.State != machine.Running {
+		return nil
+	}
+
+	probe := determineProbe(domain, component, instance.Version, ProbeLiveness)
+	if probe == nil {
+		return nil
+	}
+
+	configuration, err := model.GetConfiguration(domain.Name, component.Name, instance.UUID)
+	if err != nil {
+		return nil
+	}
+
+	return runProbe(probe, ProbeLiveness, component, configuration)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"testing"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+func TestCheckLiveness(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		locked   bool
+		expected string
+	}{
+		{"alive", "true", false, model.ActiveState},
+		{"failed", "false", false, model.FailureState},
+		{"locked", "false", true, model.ActiveState},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{"app": {}})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			template, _ := domain.GetTemplate("app")
+			variant, _ := template.GetVariant("1.0.0")
+			variant.Liveness, _ = model.NewProbe(model.HookCommand)
+			variant.Liveness.Command = []string{test.command}
+
+			instance := addTestInstance(t, domain, "app", model.ActiveState, "")
+
+			// instances which are being changed by a task are skipped
+			if test.locked {
+				tryLock(domain.Name, "app", instance.UUID, "task")
+				defer unlock(domain.Name, "app", instance.UUID, "task")
+			}
+
+			events := CheckLiveness(domain)
+
+			if instance.State != test.expected {
+				t.Errorf("expected state %s, got %s", test.expected, instance.State)
+			}
+			if failed := test.expected == model.FailureState; (len(events) == 1) != failed {
+				t.Errorf("unexpected events %v", driftDetails(events))
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
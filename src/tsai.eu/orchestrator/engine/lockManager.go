@@ -0,0 +1,209 @@
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+
+// LockModeQueue indicates that tasks wait until a locked instance is released
+const LockModeQueue string = "queue"
+
+// LockModeFail indicates that tasks fail if an instance is locked
+const LockModeFail string = "fail"
+
+//------------------------------------------------------------------------------
+
+// InstanceLock grants a task exclusive access to an instance.
+type InstanceLock struct {
+	Key     string   `yaml:"key"`     // domain/component/instance
+	Holder  string   `yaml:"holder"`  // uuid of the task holding the lock
+	Since   int64    `yaml:"since"`   // time at which the lock has been acquired (nsecs since 1.1.1970)
+	Waiting []string `yaml:"waiting"` // uuids of the tasks waiting for the lock
+}
+
+// LockManager keeps track of the locks of all instances.
+type LockManager struct {
+	sync.Mutex
+	Mode  string                   // behaviour if an instance is locked (queue/fail)
+	Locks map[string]*InstanceLock // locks per domain/component/instance
+}
+
+var lockManager *LockManager
+var lockManagerOnce sync.Once
+
+//------------------------------------------------------------------------------
+
+// GetLockManager initialises and returns the lock manager.
+func GetLockManager() *LockManager {
+
+	// initialise singleton once
+	lockManagerOnce.Do(func() {
+		lockManager = &LockManager{
+			Mode:  util.LockMode(),
+			Locks: map[string]*InstanceLock{},
+		}
+	})
+
+	return lockManager
+}
+
+//------------------------------------------------------------------------------
+
+// lockKey determines the key of the lock of the instance addressed by a task.
+func lockKey(task *model.Task) string {
+	return task.Domain + "/" + task.Component + "/" + task.Instance
+}
+
+//------------------------------------------------------------------------------
+
+// Acquire obtains the lock of the instance addressed by a task. It returns
+// false if the task has to wait for the lock and an error if the lock is held
+// by another task and the manager is in fail-fast mode.
+func (manager *LockManager) Acquire(task *model.Task) (bool, error) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	key := lockKey(task)
+
+	// acquire a free lock
+	lock, found := manager.Locks[key]
+	if !found {
+		manager.Locks[key] = &InstanceLock{
+			Key:     key,
+			Holder:  task.UUID,
+			Since:   time.Now().UnixNano(),
+			Waiting: []string{},
+		}
+		return true, nil
+	}
+
+	// the task already holds the lock
+	if lock.Holder == task.UUID {
+		return true, nil
+	}
+
+	// fail fast
+	if manager.Mode == LockModeFail {
+		return false, errors.Errorf("instance '%s' is locked by '%s'", task.Instance, lock.Holder)
+	}
+
+	// wait for the lock
+	for _, uuid := range lock.Waiting {
+		if uuid == task.UUID {
+			return false, nil
+		}
+	}
+	lock.Waiting = append(lock.Waiting, task.UUID)
+
+	return false, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Release returns the lock held by a task and hands it over to the next
+// waiting task which is triggered to continue.
+func (manager *LockManager) Release(task *model.Task) {
+	manager.release(task.Domain, lockKey(task), task.UUID)
+}
+
+//------------------------------------------------------------------------------
+
+// release returns the lock held by a task or by another activity and hands it
+// over to the next waiting task which is triggered to continue.
+func (manager *LockManager) release(domainName string, key string, holder string) {
+	manager.Lock()
+
+	lock, found := manager.Locks[key]
+	if !found || lock.Holder != holder {
+		manager.Unlock()
+		return
+	}
+
+	// determine the next waiting task which is still pending
+	domain, _ := model.GetModel().GetDomain(domainName)
+
+	var next *model.Task
+	for next == nil && len(lock.Waiting) > 0 && domain != nil {
+		candidate, err := domain.GetTask(lock.Waiting[0])
+		lock.Waiting = lock.Waiting[1:]
+
+		if err == nil && candidate.GetStatus() <= model.TaskStatusExecuting {
+			next = candidate
+		}
+	}
+
+	// free the lock or hand it over
+	if next == nil {
+		delete(manager.Locks, key)
+	} else {
+		lock.Holder = next.UUID
+		lock.Since = time.Now().UnixNano()
+	}
+
+	manager.Unlock()
+
+	// trigger the next task
+	if next != nil {
+		GetEventChannel() <- model.NewEvent(next.Domain, next.UUID, model.EventTypeTaskExecution, holder)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// GetLock provides a copy of the lock of the instance addressed by a task.
+func (manager *LockManager) GetLock(task *model.Task) (*InstanceLock, error) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	lock, found := manager.Locks[lockKey(task)]
+	if !found {
+		return nil, errors.New("instance is not locked")
+	}
+
+	result := *lock
+	result.Waiting = append([]string{}, lock.Waiting...)
+
+	// success
+	return &result, nil
+}
+
+//------------------------------------------------------------------------------
+
+// tryLock obtains the lock of an instance for an activity other than a task,
+// e.g. the reconciler, if the instance is not locked.
+func tryLock(domain string, component string, instance string, holder string) bool {
+	manager := GetLockManager()
+
+	manager.Lock()
+	defer manager.Unlock()
+
+	key := domain + "/" + component + "/" + instance
+	if _, found := manager.Locks[key]; found {
+		return false
+	}
+
+	manager.Locks[key] = &InstanceLock{
+		Key:     key,
+		Holder:  holder,
+		Since:   time.Now().UnixNano(),
+		Waiting: []string{},
+	}
+
+	return true
+}
+
+//------------------------------------------------------------------------------
+
+// unlock returns a lock obtained by tryLock. Tasks which have been waiting for
+// the lock in the meantime are triggered to continue.
+func unlock(domain string, component string, instance string, holder string) {
+	GetLockManager().release(domain, domain+"/"+component+"/"+instance, holder)
+}
+
+//------------------------------------------------------------------------------
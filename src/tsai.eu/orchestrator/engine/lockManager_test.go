@@ -0,0 +1,233 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// lockStep is a single operation of a task on the lock manager.
+type lockStep struct {
+	task      int    // index of the task
+	action    string // acquire/release/complete
+	acquired  bool   // expected result of acquire
+	fails     bool   // acquire is expected to fail
+	triggered int    // index of the task expected to be triggered by release (-1 = none)
+}
+
+//------------------------------------------------------------------------------
+
+// releaseLock releases a lock and determines the task which has been triggered.
+func releaseLock(t *testing.T, manager *LockManager, task *model.Task) string {
+	done := make(chan bool)
+	go func() {
+		manager.Release(task)
+		close(done)
+	}()
+
+	select {
+	case event := <-GetEventChannel():
+		<-done
+		return event.Task
+	case <-done:
+		return ""
+	case <-time.After(time.Second):
+		t.Fatalf("release has not returned")
+	}
+	return ""
+}
+
+//------------------------------------------------------------------------------
+
+func TestLockManager(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		steps   []lockStep
+		holder  int
+		waiting []int
+	}{
+		{
+			name:    "free lock",
+			mode:    LockModeQueue,
+			steps:   []lockStep{{task: 0, action: "acquire", acquired: true}},
+			holder:  0,
+			waiting: []int{},
+		},
+		{
+			name: "reentrant",
+			mode: LockModeQueue,
+			steps: []lockStep{
+				{task: 0, action: "acquire", acquired: true},
+				{task: 0, action: "acquire", acquired: true},
+			},
+			holder:  0,
+			waiting: []int{},
+		},
+		{
+			name: "queue",
+			mode: LockModeQueue,
+			steps: []lockStep{
+				{task: 0, action: "acquire", acquired: true},
+				{task: 1, action: "acquire", acquired: false},
+				{task: 2, action: "acquire", acquired: false},
+				{task: 1, action: "acquire", acquired: false},
+			},
+			holder:  0,
+			waiting: []int{1, 2},
+		},
+		{
+			name: "other instance",
+			mode: LockModeQueue,
+			steps: []lockStep{
+				{task: 0, action: "acquire", acquired: true},
+				{task: 3, action: "acquire", acquired: true},
+			},
+			holder:  0,
+			waiting: []int{},
+		},
+		{
+			name: "release",
+			mode: LockModeQueue,
+			steps: []lockStep{
+				{task: 0, action: "acquire", acquired: true},
+				{task: 0, action: "release", triggered: -1},
+			},
+			holder: -1,
+		},
+		{
+			name: "hand over",
+			mode: LockModeQueue,
+			steps: []lockStep{
+				{task: 0, action: "acquire", acquired: true},
+				{task: 1, action: "acquire", acquired: false},
+				{task: 2, action: "acquire", acquired: false},
+				{task: 0, action: "release", triggered: 1},
+				{task: 1, action: "acquire", acquired: true},
+			},
+			holder:  1,
+			waiting: []int{2},
+		},
+		{
+			name: "skip finished tasks",
+			mode: LockModeQueue,
+			steps: []lockStep{
+				{task: 0, action: "acquire", acquired: true},
+				{task: 1, action: "acquire", acquired: false},
+				{task: 2, action: "acquire", acquired: false},
+				{task: 1, action: "complete"},
+				{task: 0, action: "release", triggered: 2},
+			},
+			holder:  2,
+			waiting: []int{},
+		},
+		{
+			name: "release without lock",
+			mode: LockModeQueue,
+			steps: []lockStep{
+				{task: 0, action: "acquire", acquired: true},
+				{task: 1, action: "acquire", acquired: false},
+				{task: 1, action: "release", triggered: -1},
+			},
+			holder:  0,
+			waiting: []int{1},
+		},
+		{
+			name: "fail fast",
+			mode: LockModeFail,
+			steps: []lockStep{
+				{task: 0, action: "acquire", acquired: true},
+				{task: 1, action: "acquire", acquired: false, fails: true},
+			},
+			holder:  0,
+			waiting: []int{},
+		},
+		{
+			name: "fail fast for other instance",
+			mode: LockModeFail,
+			steps: []lockStep{
+				{task: 0, action: "acquire", acquired: true},
+				{task: 3, action: "acquire", acquired: true},
+			},
+			holder:  0,
+			waiting: []int{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{"app": {}})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			// tasks 0-2 address the same instance, task 3 another instance
+			tasks := []*model.Task{}
+			for _, instance := range []string{"i1", "i1", "i1", "i2"} {
+				task := model.Task{
+					Type:      "InstanceTask",
+					Domain:    domain.Name,
+					Component: "app",
+					Instance:  instance,
+					UUID:      uuid.New().String(),
+					Status:    model.TaskStatusExecuting,
+				}
+				domain.AddTask(&task)
+				tasks = append(tasks, &task)
+			}
+
+			manager := &LockManager{Mode: test.mode, Locks: map[string]*InstanceLock{}}
+
+			for index, step := range test.steps {
+				task := tasks[step.task]
+
+				switch step.action {
+				case "acquire":
+					acquired, err := manager.Acquire(task)
+					if acquired != step.acquired || (err != nil) != step.fails {
+						t.Fatalf("step %d: expected %v/%v, got %v/%v", index, step.acquired, step.fails, acquired, err)
+					}
+				case "release":
+					expected := ""
+					if step.triggered >= 0 {
+						expected = tasks[step.triggered].UUID
+					}
+					if triggered := releaseLock(t, manager, task); triggered != expected {
+						t.Fatalf("step %d: expected task %s to be triggered, got %s", index, expected, triggered)
+					}
+				case "complete":
+					task.Status = model.TaskStatusCompleted
+				}
+			}
+
+			// check the lock of the shared instance
+			lock, err := manager.GetLock(tasks[0])
+			if test.holder < 0 {
+				if err == nil {
+					t.Errorf("expected no lock, got %v", lock)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if lock.Holder != tasks[test.holder].UUID {
+				t.Errorf("expected holder %s, got %s", tasks[test.holder].UUID, lock.Holder)
+			}
+
+			waiting := []string{}
+			for _, index := range test.waiting {
+				waiting = append(waiting, tasks[index].UUID)
+			}
+			if !reflect.DeepEqual(lock.Waiting, waiting) {
+				t.Errorf("expected waiting tasks %v, got %v", waiting, lock.Waiting)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
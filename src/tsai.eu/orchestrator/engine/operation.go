@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// NewServiceExecutionTask creates a new task which moves a single service of
+// a domain towards the setup defined by an architecture. The other services
+// of the architecture are not touched.
+func NewServiceExecutionTask(domain string, architecture string, service string) (model.Task, error) {
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return model.Task{}, errors.New("unknown domain")
+	}
+
+	// get architecture
+	a, err := d.GetArchitecture(architecture)
+	if err != nil {
+		return model.Task{}, errors.New("unknown architecture")
+	}
+
+	// get service
+	if _, err = a.GetService(service); err != nil {
+		return model.Task{}, errors.New("unknown service")
+	}
+
+	// determine the required changes of the service
+	servicePlan := planService(d, a, service)
+
+	// success
+	return NewServiceTask(domain, "", architecture, servicePlan)
+}
+
+//------------------------------------------------------------------------------
+
+// NewInstanceTransitionTask creates a new task which moves a single instance
+// of a component to a desired state.
+func NewInstanceTransitionTask(domain string, component string, instance string, state string) (model.Task, error) {
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return model.Task{}, errors.New("unknown domain")
+	}
+
+	// get component
+	c, err := d.GetComponent(component)
+	if err != nil {
+		return model.Task{}, errors.New("unknown component")
+	}
+
+	// get instance
+	i, err := c.GetInstance(instance)
+	if err != nil {
+		return model.Task{}, errors.New("unknown instance")
+	}
+
+	// check state
+	if !model.IsValidComponentState(c.Type, state) {
+		return model.Task{}, errors.New("invalid state")
+	}
+
+	// success
+	return NewInstanceTask(domain, "", "", component, i.Version, instance, state)
+}
+
+//------------------------------------------------------------------------------
+
+// NewScaleTask creates a new task which adjusts the number of instances of a
+// version of a component. Missing instances are created in the state of the
+// existing instances of the version (by default in the running state of the
+// state machine of the component type) and surplus instances are removed.
+// Instances of other versions are not touched.
+func NewScaleTask(domain string, component string, version string, size int) (model.Task, error) {
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return model.Task{}, errors.New("unknown domain")
+	}
+
+	// check size
+	if size < 0 {
+		return model.Task{}, errors.New("invalid size")
+	}
+
+	// get template variant
+	template, err := d.GetTemplate(component)
+	if err != nil {
+		return model.Task{}, errors.New("unknown template")
+	}
+
+	if _, err = template.GetVariant(version); err != nil {
+		return model.Task{}, errors.New("unknown variant")
+	}
+
+	// determine the existing instances of the version
+	machine := model.GetStateMachine(template.Type)
+	instances := []*model.Instance{}
+
+	if c, err := d.GetComponent(component); err == nil {
+		machine = model.GetStateMachine(c.Type)
+
+		uuids, _ := c.ListInstances()
+		sort.Strings(uuids)
+		for _, uuid := range uuids {
+			instance, err := c.GetInstance(uuid)
+			if err != nil || instance.Version != version || instance.State == machine.Initial {
+				continue
+			}
+			instances = append(instances, instance)
+		}
+	}
+
+	state := machine.Running
+	if len(instances) > 0 {
+		state = instances[0].State
+	}
+
+	// new instances require a valid state
+	if size > len(instances) && !machine.IsValidState(state) {
+		return model.Task{}, errors.New("unable to determine the state of new instances")
+	}
+
+	// determine the required changes
+	servicePlan := ServicePlan{
+		Service: component,
+		Actions: []*PlanAction{},
+	}
+
+	for index := len(instances); index < size; index++ {
+		servicePlan.Actions = append(servicePlan.Actions, &PlanAction{
+			Action:   PlanActionCreate,
+			Version:  version,
+			Instance: uuid.New().String(),
+			Current:  machine.Initial,
+			State:    state,
+		})
+	}
+
+	for index := size; index < len(instances); index++ {
+		servicePlan.Actions = append(servicePlan.Actions, &PlanAction{
+			Action:   PlanActionRemove,
+			Version:  version,
+			Instance: instances[index].UUID,
+			Current:  instances[index].State,
+			State:    machine.Initial,
+		})
+	}
+
+	// success
+	return NewServiceTask(domain, "", "", &servicePlan)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// instanceTargets counts the target states of the instance tasks below a task.
+func instanceTargets(domain *model.Domain, uuid string) map[string]int {
+	targets := map[string]int{}
+
+	task, err := domain.GetTask(uuid)
+	if err != nil {
+		return targets
+	}
+
+	if task.Type == "InstanceTask" {
+		targets[task.State]++
+	}
+
+	for _, subtask := range task.GetSubtasks() {
+		for state, count := range instanceTargets(domain, subtask) {
+			targets[state] += count
+		}
+	}
+
+	return targets
+}
+
+//------------------------------------------------------------------------------
+
+func TestNewScaleTask(t *testing.T) {
+	// component type without a running state
+	machine, _ := model.NewStateMachine("provisioning", "initial", "failure")
+	machine.AddState("provisioned")
+	machine.AddTransition("create", "initial", "provisioned", "", "")
+	machine.AddTransition("destroy", "provisioned", "initial", "", "")
+	machine.AddTransition("reset", "failure", "initial", "", "")
+	if err := model.RegisterStateMachine("provisioning", machine); err != nil {
+		t.Fatalf("unable to register state machine: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		ctype     string
+		instances []string
+		version   string
+		size      int
+		expected  map[string]int
+		fails     bool
+	}{
+		{"unchanged", "test", []string{"active", "active"}, "1.0.0", 2, map[string]int{}, false},
+		{"up from zero", "test", []string{}, "1.0.0", 2, map[string]int{"active": 2}, false},
+		{"up in existing state", "test", []string{"inactive"}, "1.0.0", 3, map[string]int{"inactive": 2}, false},
+		{"up ignoring initial instances", "test", []string{"initial", "active"}, "1.0.0", 2, map[string]int{"active": 1}, false},
+		{"down", "test", []string{"active", "active", "active"}, "1.0.0", 1, map[string]int{"initial": 2}, false},
+		{"to zero", "test", []string{"active", "active"}, "1.0.0", 0, map[string]int{"initial": 2}, false},
+		{"down without running state", "provisioning", []string{"provisioned", "provisioned"}, "1.0.0", 1, map[string]int{"initial": 1}, false},
+		{"to zero without running state", "provisioning", []string{"provisioned"}, "1.0.0", 0, map[string]int{"initial": 1}, false},
+		{"up without running state", "provisioning", []string{}, "1.0.0", 1, nil, true},
+		{"up in existing state without running state", "provisioning", []string{"provisioned"}, "1.0.0", 2, map[string]int{"provisioned": 1}, false},
+		{"negative size", "test", []string{}, "1.0.0", -1, nil, true},
+		{"unknown variant", "test", []string{}, "2.0.0", 1, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{"app": {}})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			template, _ := domain.GetTemplate("app")
+			template.Type = test.ctype
+
+			component, _ := model.NewComponent("app", test.ctype)
+			domain.AddComponent(component)
+
+			for _, state := range test.instances {
+				addTestInstance(t, domain, "app", state, "")
+			}
+
+			task, err := NewScaleTask(domain.Name, "app", test.version, test.size)
+			if test.fails {
+				if err == nil {
+					t.Errorf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			targets := instanceTargets(domain, task.UUID)
+			if !reflect.DeepEqual(targets, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, targets)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
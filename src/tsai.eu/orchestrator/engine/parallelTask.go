@@ -29,11 +29,7 @@ func NewParallelTask(domain string, parent string, subtasks []string) (model.Tas
 	task.Subtasks = subtasks
 
 	// add handlers
-	task.SetExecute(ExecuteParallelTask)
-	task.SetTerminate(TerminateTask)
-	task.SetFailed(FailedTask)
-	task.SetTimeout(TimeoutTask)
-	task.SetCompleted(CompletedTask)
+	BindHandlers(&task)
 
 	// get domain
 	d, err := model.GetModel().GetDomain(domain)
@@ -92,8 +88,8 @@ func ExecuteParallelTask(task *model.Task) {
 
 	// initially trigger all subtasks
 	if status == model.TaskStatusInitial {
-		// update status
-		task.Status = model.TaskStatusExecuting
+		// start the execution
+		startTask(task)
 
 		// execute all subtasks
 		for _, subtask := range task.Subtasks {
@@ -105,7 +101,12 @@ func ExecuteParallelTask(task *model.Task) {
 	// check status of currently running subtasks
 	completed := 0
 	for _, suuid := range task.Subtasks {
-		subtask, _ := domain.GetTask(suuid)
+		subtask, err := domain.GetTask(suuid)
+		if err != nil {
+			task.SetError(err)
+			channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+			return
+		}
 
 		switch subtask.GetStatus() {
 		// do nothing if subtask has not started yet or is still executing
@@ -115,7 +116,7 @@ func ExecuteParallelTask(task *model.Task) {
 			completed++
 		// check if subtask has failed
 		case model.TaskStatusTerminated, model.TaskStatusFailed, model.TaskStatusTimeout:
-			task.Status = model.TaskStatusFailed
+			task.SetStatus(model.TaskStatusFailed)
 			// inform parent of failure
 			if task.Parent != "" {
 				channel <- model.NewEvent(task.Domain, task.Parent, model.EventTypeTaskFailure, task.UUID)
@@ -130,7 +131,7 @@ func ExecuteParallelTask(task *model.Task) {
 
 	// check if task has completed
 	if completed == len(task.Subtasks) {
-		task.Status = model.TaskStatusCompleted
+		task.SetStatus(model.TaskStatusCompleted)
 		// retrigger parent execution
 		if task.Parent != "" {
 			channel <- model.NewEvent(task.Domain, task.Parent, model.EventTypeTaskExecution, task.UUID)
@@ -0,0 +1,310 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+
+// PlanActionUpdate indicates an existing instance is transitioned to another state
+const PlanActionUpdate string = "update"
+
+// PlanActionCreate indicates a new instance is created
+const PlanActionCreate string = "create"
+
+// PlanActionRemove indicates an existing instance is removed
+const PlanActionRemove string = "remove"
+
+//------------------------------------------------------------------------------
+
+// Plan captures all changes required to move a domain towards an architecture.
+type Plan struct {
+	Domain       string                  `yaml:"domain" json:"domain"`             // name of the domain
+	Architecture string                  `yaml:"architecture" json:"architecture"` // name of the architecture
+	Fingerprint  string                  `yaml:"fingerprint" json:"fingerprint"`   // fingerprint of the model the plan is based upon
+	Waves        [][]string              `yaml:"waves" json:"waves"`               // services in order of processing
+	Services     map[string]*ServicePlan `yaml:"services" json:"services"`         // changes per service
+	Gates        []*PlanGate             `yaml:"gates" json:"gates"`               // manual approvals between waves
+}
+
+// PlanGate captures a manual approval required after a wave of services.
+type PlanGate struct {
+	Name   string `yaml:"name" json:"name"`     // name of the approval gate
+	Wave   int    `yaml:"wave" json:"wave"`     // index of the wave after which the approval is required
+	Expiry int    `yaml:"expiry" json:"expiry"` // time after which the approval times out in seconds (0 = never)
+}
+
+// ServicePlan captures the changes required for a single service.
+type ServicePlan struct {
+	Service  string                `yaml:"service" json:"service"`                       // name of the service
+	Strategy *model.UpdateStrategy `yaml:"strategy,omitempty" json:"strategy,omitempty"` // replacement of the instances (optional)
+	Release  *model.ReleasePolicy  `yaml:"release,omitempty" json:"release,omitempty"`   // release of a new version (optional)
+	Version  string                `yaml:"version,omitempty" json:"version,omitempty"`   // version to be released
+	Actions  []*PlanAction         `yaml:"actions" json:"actions"`                       // changes of the instances of the service
+}
+
+// PlanAction captures the change of a single instance.
+type PlanAction struct {
+	Action   string `yaml:"action" json:"action"`                     // type of change (update/create/remove)
+	Version  string `yaml:"version" json:"version"`                   // version of the instance
+	Instance string `yaml:"instance" json:"instance"`                 // uuid of the instance
+	Current  string `yaml:"current" json:"current"`                   // current state of the instance
+	State    string `yaml:"state" json:"state"`                       // desired state of the instance
+	Reason   string `yaml:"reason,omitempty" json:"reason,omitempty"` // reason for reconfiguring an instance (optional)
+}
+
+//------------------------------------------------------------------------------
+
+// NewPlan determines the changes required to move a domain towards an
+// architecture without modifying the model.
+func NewPlan(domain *model.Domain, architecture *model.Architecture) (*Plan, error) {
+	if domain == nil || architecture == nil {
+		return nil, errors.New("undefined domain or architecture")
+	}
+
+	// determine the order in which the services need to be processed
+	graph, err := NewServiceGraph(domain, architecture)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := Plan{
+		Domain:       domain.Name,
+		Architecture: architecture.Name,
+		Fingerprint:  determineFingerprint(domain, architecture),
+		Waves:        graph.Waves(),
+		Services:     map[string]*ServicePlan{},
+		Gates:        []*PlanGate{},
+	}
+
+	// determine the changes of each service
+	for _, wave := range plan.Waves {
+		for _, service := range wave {
+			plan.Services[service] = planService(domain, architecture, service)
+		}
+	}
+
+	// determine the position of the approval gates
+	plan.Gates, err = planGates(architecture, plan.Waves)
+	if err != nil {
+		return nil, err
+	}
+
+	// success
+	return &plan, nil
+}
+
+//------------------------------------------------------------------------------
+
+// planGates determines the waves after which the approval gates of an
+// architecture need to be passed. Gates following the last wave are omitted
+// since there are no further changes to be approved.
+func planGates(architecture *model.Architecture, waves [][]string) ([]*PlanGate, error) {
+	gates := []*PlanGate{}
+
+	// determine the wave of each service
+	position := map[string]int{}
+	for index, wave := range waves {
+		for _, service := range wave {
+			position[service] = index
+		}
+	}
+
+	for _, gate := range architecture.Gates {
+		if err := gate.Validate(); err != nil {
+			return nil, err
+		}
+
+		// the gate follows the last wave of the services to be approved
+		wave := -1
+		for _, service := range gate.After {
+			index, found := position[service]
+			if !found {
+				return nil, errors.Errorf("approval gate '%s' refers to unknown service '%s'", gate.Name, service)
+			}
+			if index > wave {
+				wave = index
+			}
+		}
+
+		if wave < len(waves)-1 {
+			gates = append(gates, &PlanGate{Name: gate.Name, Wave: wave, Expiry: gate.Expiry})
+		}
+	}
+
+	// success
+	return gates, nil
+}
+
+//------------------------------------------------------------------------------
+
+// determineFingerprint calculates a fingerprint of the current instances of a
+// domain and of the architecture which allows to detect changes of the model.
+func determineFingerprint(domain *model.Domain, architecture *model.Architecture) string {
+	hash := sha256.New()
+
+	// current instances
+	components, _ := domain.ListComponents()
+	sort.Strings(components)
+	for _, name := range components {
+		component, _ := domain.GetComponent(name)
+
+		instances, _ := component.ListInstances()
+		sort.Strings(instances)
+		for _, uuid := range instances {
+			instance, _ := component.GetInstance(uuid)
+
+			fmt.Fprintf(hash, "%s/%s/%s/%s\n", name, uuid, instance.Version, instance.State)
+		}
+	}
+
+	// target architecture
+	if architecture != nil {
+		definition, _ := architecture.Show()
+		fmt.Fprint(hash, definition)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+//------------------------------------------------------------------------------
+
+// Verify checks that the model has not changed since the plan has been created.
+func (plan *Plan) Verify() error {
+	domain, err := model.GetModel().GetDomain(plan.Domain)
+	if err != nil {
+		return err
+	}
+
+	architecture, err := domain.GetArchitecture(plan.Architecture)
+	if err != nil {
+		return err
+	}
+
+	if determineFingerprint(domain, architecture) != plan.Fingerprint {
+		return errors.New("model has changed since the plan has been created")
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// Show displays the plan in the requested format (text, yaml or json).
+func (plan *Plan) Show(format string) (string, error) {
+	switch format {
+	case "", "text":
+		return plan.Text(), nil
+	case "yaml":
+		return util.ConvertToYAML(plan)
+	case "json":
+		return util.ConvertToJSON(plan)
+	}
+
+	return "", errors.New("unknown format")
+}
+
+//------------------------------------------------------------------------------
+
+// Save writes the plan to a file in the requested format (yaml or json).
+func (plan *Plan) Save(filename string, format string) error {
+	switch format {
+	case "", "yaml":
+		return util.SaveYAML(filename, plan)
+	case "json":
+		data, err := util.ConvertToJSON(plan)
+		if err != nil {
+			return err
+		}
+		return util.SaveFile(filename, data)
+	}
+
+	return errors.New("unknown format")
+}
+
+//------------------------------------------------------------------------------
+
+// Load reads the plan from a yaml or json file
+func (plan *Plan) Load(filename string) error {
+	return util.LoadYAML(filename, plan)
+}
+
+//------------------------------------------------------------------------------
+
+// Changes determines the number of changes of the plan.
+func (plan *Plan) Changes() int {
+	changes := 0
+	for _, servicePlan := range plan.Services {
+		changes += len(servicePlan.Actions)
+	}
+	return changes
+}
+
+//------------------------------------------------------------------------------
+
+// Text provides a human readable description of the plan.
+func (plan *Plan) Text() string {
+	var text strings.Builder
+
+	count := map[string]int{}
+
+	fmt.Fprintf(&text, "plan for architecture '%s' in domain '%s'\n", plan.Architecture, plan.Domain)
+	fmt.Fprintf(&text, "fingerprint: %s\n", plan.Fingerprint)
+
+	for index, wave := range plan.Waves {
+		fmt.Fprintf(&text, "wave %d:\n", index+1)
+
+		for _, service := range wave {
+			servicePlan, found := plan.Services[service]
+			if !found || len(servicePlan.Actions) == 0 {
+				fmt.Fprintf(&text, "    %s: no changes\n", service)
+				continue
+			}
+
+			switch {
+			case servicePlan.Release != nil:
+				fmt.Fprintf(&text, "    %s (%s release of %s):\n", service, servicePlan.Release.Mode, servicePlan.Version)
+			case servicePlan.Strategy != nil:
+				fmt.Fprintf(&text, "    %s (%s):\n", service, servicePlan.Strategy.Type)
+			default:
+				fmt.Fprintf(&text, "    %s:\n", service)
+			}
+			for _, action := range servicePlan.Actions {
+				symbol := map[string]string{PlanActionUpdate: "~", PlanActionCreate: "+", PlanActionRemove: "-"}[action.Action]
+
+				fmt.Fprintf(&text, "      %s %-6s %s %s: %s -> %s", symbol, action.Action, action.Instance, action.Version, action.Current, action.State)
+				if action.Reason != "" {
+					fmt.Fprintf(&text, " (%s)", action.Reason)
+				}
+				fmt.Fprintln(&text)
+
+				count[action.Action]++
+			}
+		}
+
+		for _, gate := range plan.Gates {
+			if gate.Wave == index {
+				fmt.Fprintf(&text, "gate '%s': approval required", gate.Name)
+				if gate.Expiry > 0 {
+					fmt.Fprintf(&text, " within %ds", gate.Expiry)
+				}
+				fmt.Fprintln(&text)
+			}
+		}
+	}
+
+	fmt.Fprintf(&text, "summary: %d to create, %d to update, %d to remove\n", count[PlanActionCreate], count[PlanActionUpdate], count[PlanActionRemove])
+
+	return text.String()
+}
+
+//------------------------------------------------------------------------------
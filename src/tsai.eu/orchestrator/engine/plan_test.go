@@ -0,0 +1,304 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// testSetup describes the instances of a service within a test architecture.
+type testSetup struct {
+	service string
+	version string
+	state   string
+	size    int
+}
+
+//------------------------------------------------------------------------------
+
+// addTestArchitecture adds an architecture with the given setups to a domain.
+func addTestArchitecture(t *testing.T, domain *model.Domain, name string, setups ...testSetup) *model.Architecture {
+	architecture, _ := model.NewArchitecture(name)
+
+	for _, s := range setups {
+		service, err := architecture.GetService(s.service)
+		if err != nil {
+			service, _ = model.NewService(s.service)
+			architecture.AddService(service)
+		}
+
+		setup, _ := model.NewSetup(s.version, s.version, s.state, s.size)
+		service.AddSetup(setup)
+	}
+
+	if err := domain.AddArchitecture(architecture); err != nil {
+		t.Fatalf("unable to add architecture: %v", err)
+	}
+
+	return architecture
+}
+
+//------------------------------------------------------------------------------
+
+// describeActions summarises the actions of a service plan independent of the
+// uuids of the instances.
+func describeActions(servicePlan *ServicePlan) []string {
+	actions := []string{}
+	for _, action := range servicePlan.Actions {
+		description := fmt.Sprintf("%s %s %s->%s", action.Action, action.Version, action.Current, action.State)
+		if action.Reason != "" {
+			description += " (" + action.Reason + ")"
+		}
+		actions = append(actions, description)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+//------------------------------------------------------------------------------
+
+func TestPlanService(t *testing.T) {
+	tests := []struct {
+		name      string
+		instances []string
+		outdated  bool
+		setups    []testSetup
+		expected  []string
+	}{
+		{
+			name:     "create",
+			setups:   []testSetup{{"app", "1.0.0", "active", 2}},
+			expected: []string{"create 1.0.0 initial->active", "create 1.0.0 initial->active"},
+		},
+		{
+			name:      "unchanged",
+			instances: []string{"active", "active"},
+			setups:    []testSetup{{"app", "1.0.0", "active", 2}},
+			expected:  []string{},
+		},
+		{
+			name:      "change of state",
+			instances: []string{"inactive", "active"},
+			setups:    []testSetup{{"app", "1.0.0", "active", 2}},
+			expected:  []string{"update 1.0.0 inactive->active"},
+		},
+		{
+			name:      "scale up",
+			instances: []string{"active"},
+			setups:    []testSetup{{"app", "1.0.0", "active", 2}},
+			expected:  []string{"create 1.0.0 initial->active"},
+		},
+		{
+			name:      "scale down",
+			instances: []string{"active", "active", "active"},
+			setups:    []testSetup{{"app", "1.0.0", "active", 1}},
+			expected:  []string{"remove 1.0.0 active->initial", "remove 1.0.0 active->initial"},
+		},
+		{
+			name:      "change of version",
+			instances: []string{"active"},
+			setups:    []testSetup{{"app", "2.0.0", "active", 1}},
+			expected:  []string{"create 2.0.0 initial->active", "remove 1.0.0 active->initial"},
+		},
+		{
+			name:      "not part of the architecture",
+			instances: []string{"active"},
+			setups:    []testSetup{{"db", "1.0.0", "active", 1}},
+			expected:  []string{"remove 1.0.0 active->initial"},
+		},
+		{
+			name:      "changed configuration",
+			instances: []string{"active"},
+			outdated:  true,
+			setups:    []testSetup{{"app", "1.0.0", "active", 1}},
+			expected:  []string{"update 1.0.0 active->active (configuration changed)"},
+		},
+		{
+			name:      "changed configuration and state",
+			instances: []string{"inactive"},
+			outdated:  true,
+			setups:    []testSetup{{"app", "1.0.0", "active", 1}},
+			expected:  []string{"update 1.0.0 inactive->active (configuration changed)"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{"app": {}, "db": {}})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			template, _ := domain.GetTemplate("app")
+			variant, _ := model.NewVariant("2.0.0", "app-configuration-2")
+			template.AddVariant(variant)
+
+			for _, state := range test.instances {
+				instance := addTestInstance(t, domain, "app", state, "")
+				if test.outdated {
+					instance.Fingerprint = "outdated"
+				}
+			}
+
+			architecture := addTestArchitecture(t, domain, "architecture", test.setups...)
+
+			actions := describeActions(planService(domain, architecture, "app"))
+			if !reflect.DeepEqual(actions, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, actions)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestNewPlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		gates   []*model.ApprovalGate
+		waves   [][]string
+		changes int
+		gated   []PlanGate
+		fails   bool
+	}{
+		{
+			name:    "without gates",
+			waves:   [][]string{{"net"}, {"db"}, {"app"}},
+			changes: 3,
+			gated:   []PlanGate{},
+		},
+		{
+			name: "gates",
+			gates: []*model.ApprovalGate{
+				{Name: "network", After: []string{"net"}, Expiry: 60},
+				{Name: "backend", After: []string{"net", "db"}},
+			},
+			waves:   [][]string{{"net"}, {"db"}, {"app"}},
+			changes: 3,
+			gated:   []PlanGate{{Name: "network", Wave: 0, Expiry: 60}, {Name: "backend", Wave: 1}},
+		},
+		{
+			name:    "gate after the last wave",
+			gates:   []*model.ApprovalGate{{Name: "final", After: []string{"app"}}},
+			waves:   [][]string{{"net"}, {"db"}, {"app"}},
+			changes: 3,
+			gated:   []PlanGate{},
+		},
+		{
+			name:  "gate after an unknown service",
+			gates: []*model.ApprovalGate{{Name: "unknown", After: []string{"web"}}},
+			fails: true,
+		},
+		{
+			name:  "invalid gate",
+			gates: []*model.ApprovalGate{{Name: "", After: []string{"net"}}},
+			fails: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, testDependencies)
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			architecture := addTestArchitecture(t, domain, "architecture",
+				testSetup{"net", "1.0.0", "active", 1},
+				testSetup{"db", "1.0.0", "active", 1},
+				testSetup{"app", "1.0.0", "active", 1})
+			architecture.Gates = test.gates
+
+			plan, err := NewPlan(domain, architecture)
+			if test.fails {
+				if err == nil {
+					t.Errorf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(plan.Waves, test.waves) {
+				t.Errorf("expected waves %v, got %v", test.waves, plan.Waves)
+			}
+			if plan.Changes() != test.changes {
+				t.Errorf("expected %d changes, got %d", test.changes, plan.Changes())
+			}
+
+			gated := []PlanGate{}
+			for _, gate := range plan.Gates {
+				gated = append(gated, *gate)
+			}
+			if !reflect.DeepEqual(gated, test.gated) {
+				t.Errorf("expected gates %v, got %v", test.gated, gated)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestPlanVerify(t *testing.T) {
+	tests := []struct {
+		name          string
+		configuration bool   // configuration of "app" changes
+		instance      bool   // instance of "app" is added
+		state         string // new state of the instance of "db"
+		size          int    // new size of the setup of "app"
+		fails         bool
+	}{
+		{name: "unchanged"},
+		{name: "changed configuration", configuration: true},
+		{name: "new instance", instance: true, fails: true},
+		{name: "changed state", state: "inactive", fails: true},
+		{name: "changed architecture", size: 2, fails: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, testDependencies)
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			db := addTestInstance(t, domain, "db", "active", "db-1")
+
+			architecture := addTestArchitecture(t, domain, "architecture",
+				testSetup{"db", "1.0.0", "active", 1},
+				testSetup{"app", "1.0.0", "active", 1})
+
+			plan, err := NewPlan(domain, architecture)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if test.configuration {
+				template, _ := domain.GetTemplate("app")
+				variant, _ := template.GetVariant("1.0.0")
+				variant.Configuration = "changed"
+			}
+			if test.instance {
+				addTestInstance(t, domain, "app", "active", "")
+			}
+			if test.state != "" {
+				db.State = test.state
+			}
+			if test.size != 0 {
+				service, _ := architecture.GetService("app")
+				setup, _ := service.GetSetup("1.0.0")
+				setup.Size = test.size
+			}
+
+			err = plan.Verify()
+			if test.fails && err == nil {
+				t.Errorf("expected an error")
+			}
+			if !test.fails && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
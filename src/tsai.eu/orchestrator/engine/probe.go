@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"github.com/pkg/errors"
+	ctrl "tsai.eu/orchestrator/controller"
+	"tsai.eu/orchestrator/hook"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// ProbeReadiness identifies the check whether an instance is ready
+const ProbeReadiness string = "readiness"
+
+// ProbeLiveness identifies the check whether an instance is alive
+const ProbeLiveness string = "liveness"
+
+//------------------------------------------------------------------------------
+
+// determineProbe determines the readiness or liveness probe of an instance. A
+// probe declared by the template variant supersedes the probes of controllers
+// which are capable of verifying their instances.
+func determineProbe(domain *model.Domain, component *model.Component, version string, check string) *model.Probe {
+	template, err := domain.GetTemplate(component.Name)
+	if err == nil {
+		variant, err := template.GetVariant(version)
+		if err == nil {
+			if check == ProbeReadiness && variant.Readiness != nil {
+				return variant.Readiness
+			}
+			if check == ProbeLiveness && variant.Liveness != nil {
+				return variant.Liveness
+			}
+		}
+	}
+
+	// fall back to the capabilities of the controller
+	controller, err := ctrl.GetController(component.Type)
+	if err == nil {
+		if _, ok := controller.(ctrl.ProbeController); ok {
+			probe, _ := model.NewProbe(model.ProbeController)
+			return probe
+		}
+	}
+
+	// no probe applies
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// runProbe verifies the readiness or liveness of an instance. The result is
+// nil if the check has passed.
+func runProbe(probe *model.Probe, check string, component *model.Component, configuration *model.ComponentConfiguration) error {
+	// delegate the check to the controller
+	if probe.Type == model.ProbeController {
+		controller, err := ctrl.GetController(component.Type)
+		if err != nil {
+			return err
+		}
+
+		probeController, ok := controller.(ctrl.ProbeController)
+		if !ok {
+			return errors.Errorf("controller of type '%s' is not able to verify instances", component.Type)
+		}
+
+		passed := false
+		if check == ProbeReadiness {
+			passed, err = probeController.Ready(configuration)
+		} else {
+			passed, err = probeController.Alive(configuration)
+		}
+
+		if err != nil {
+			return err
+		}
+		if !passed {
+			return errors.Errorf("%s check of instance '%s' has not passed", check, configuration.Instance)
+		}
+
+		// success
+		return nil
+	}
+
+	// execute the check like a hook
+	runner, err := hook.GetRunner(probe.Type)
+	if err != nil {
+		return err
+	}
+
+	definition := model.Hook{
+		Name:    check + " probe",
+		On:      check,
+		Type:    probe.Type,
+		Command: probe.Command,
+		URL:     probe.URL,
+		Method:  probe.Method,
+		Timeout: probe.Timeout,
+	}
+
+	return runner.Run(&definition, configuration)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// NewReadinessTask creates a new task which waits until a started instance is ready
+func NewReadinessTask(domain string, parent string, state string) (model.Task, error) {
+	var task model.Task
+
+	// TODO: check parameters if context exists
+	task.Type = "ReadinessTask"
+	task.Domain = domain
+	task.Architecture = ""
+	task.Component = ""
+	task.Version = ""
+	task.Instance = ""
+	task.State = state
+	task.UUID = uuid.New().String()
+	task.Parent = parent
+	task.Status = model.TaskStatusInitial
+	task.Phase = 0
+	task.Subtasks = []string{}
+
+	// add handlers
+	BindHandlers(&task)
+
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return task, errors.New("unknown domain")
+	}
+
+	// determine parent node
+	parentTask, err := d.GetTask(parent)
+	if err != nil {
+		return task, errors.New("unknown parent")
+	}
+
+	// add parent context
+	task.Architecture = parentTask.Architecture
+	task.Component = parentTask.Component
+	task.Version = parentTask.Version
+	task.Instance = parentTask.Instance
+
+	// add task to domain
+	err = d.AddTask(&task)
+	if err != nil {
+		return task, err
+	}
+
+	// success
+	return task, nil
+}
+
+//------------------------------------------------------------------------------
+
+// ExecuteReadinessTask polls the readiness probe of the instance until it has
+// passed or the deadline of the probe has been exceeded.
+func ExecuteReadinessTask(task *model.Task) {
+	// get event channel
+	channel := GetEventChannel()
+
+	// check status
+	status := task.GetStatus()
+
+	if status != model.TaskStatusInitial && status != model.TaskStatusExecuting {
+		return
+	}
+
+	// initialize if needed
+	if status == model.TaskStatusInitial {
+		// start the execution
+		startTask(task)
+	}
+
+	// determine the probe of the instance
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		task.SetError(err)
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+		return
+	}
+
+	component, err := domain.GetComponent(task.Component)
+	if err != nil {
+		task.SetError(err)
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+		return
+	}
+
+	probe := determineProbe(domain, component, task.Version, ProbeReadiness)
+	if probe == nil {
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskCompletion, task.UUID)
+		return
+	}
+
+	// check the readiness of the instance
+	configuration, err := model.GetConfiguration(domain.Name, component.Name, task.Instance)
+	if err == nil {
+		task.Attempts++
+
+		err = runProbe(probe, ProbeReadiness, component, configuration)
+	}
+
+	if err == nil {
+		task.SetError(nil)
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskCompletion, task.UUID)
+		return
+	}
+
+	// give up once the deadline has been exceeded
+	task.SetError(err)
+
+	started := task.GetStarted()
+	if started == 0 {
+		started = time.Now().UnixNano()
+	}
+	if probe.Deadline > 0 && time.Now().UnixNano() > started+int64(probe.Deadline)*int64(time.Second) {
+		task.SetError(errors.New("instance has not become ready in time: " + err.Error()))
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+		return
+	}
+
+	// check again after the interval
+	interval := probe.Interval
+	if interval <= 0 {
+		interval = 1000
+	}
+
+	time.AfterFunc(time.Duration(interval)*time.Millisecond, func() {
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskExecution, task.UUID)
+	})
+}
+
+//------------------------------------------------------------------------------
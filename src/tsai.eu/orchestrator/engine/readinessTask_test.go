@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+func TestReadinessTask(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		expected model.TaskStatus
+	}{
+		{"ready", "true", model.TaskStatusCompleted},
+		{"deadline", "false", model.TaskStatusFailed},
+	}
+
+	dispatcher := startTestDispatcher()
+	defer stopTestDispatcher(t, dispatcher)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{"app": {}})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			// the timeout of the task exceeds the deadline of the probe
+			template, _ := domain.GetTemplate("app")
+			variant, _ := template.GetVariant("1.0.0")
+			variant.Readiness, _ = model.NewProbe(model.HookCommand)
+			variant.Readiness.Command = []string{test.command}
+			variant.Readiness.Interval = 10
+			variant.Readiness.Deadline = 1
+			variant.Readiness.Timeout = 5
+
+			instance := addTestInstance(t, domain, "app", model.ActiveState, "")
+
+			parent := newTestTask(t, domain, "")
+			parent.Component = "app"
+			parent.Version = "1.0.0"
+			parent.Instance = instance.UUID
+
+			created, err := NewReadinessTask(domain.Name, parent.UUID, model.ActiveState)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			task := executeTestTask(t, dispatcher, domain, created.UUID)
+
+			if status := task.GetStatus(); status != test.expected {
+				t.Errorf("expected status %v, got %v", test.expected, status)
+			}
+			if test.expected == model.TaskStatusFailed && !strings.Contains(task.GetError(), "has not become ready in time") {
+				t.Errorf("unexpected error '%s'", task.GetError())
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
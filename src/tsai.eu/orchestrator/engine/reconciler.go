@@ -0,0 +1,259 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ctrl "tsai.eu/orchestrator/controller"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// reportedDrift keeps track of the drift which has already been recorded
+// (domain/component/instance -> description) to avoid duplicate events.
+var reportedDrift = map[string]string{}
+var reportedDriftLock sync.Mutex
+
+//------------------------------------------------------------------------------
+
+// StartReconciler periodically reconciles all domains of the model.
+func StartReconciler(m *model.Model, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			Reconcile(m)
+		}
+	}()
+}
+
+//------------------------------------------------------------------------------
+
+// Reconcile reconciles all domains of the model.
+func Reconcile(m *model.Model) {
+	domains, _ := m.ListDomains()
+	for _, name := range domains {
+		domain, err := m.GetDomain(name)
+		if err != nil {
+			continue
+		}
+
+		ReconcileDomain(domain)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// ReconcileDomain compares the recorded state of all instances of a domain with
+// the state reported by their controllers and with the desired state defined by
+// the architecture of the domain. Newly detected drift is recorded as events and
+// corrected if the domain requests so. Instances which are locked by a task and
+// services which are being changed by a task are skipped.
+func ReconcileDomain(domain *model.Domain) ([]*model.Event, error) {
+	drift := map[string]string{}
+
+	// compare the recorded states with the reported states
+	components, _ := domain.ListComponents()
+	sort.Strings(components)
+	for _, name := range components {
+		component, _ := domain.GetComponent(name)
+
+		controller, err := ctrl.GetController(component.Type)
+		if err != nil {
+			continue
+		}
+
+		instances, _ := component.ListInstances()
+		sort.Strings(instances)
+		for _, uuid := range instances {
+			// instances which are being changed by a task are skipped
+			if !tryLock(domain.Name, name, uuid, "reconciler") {
+				continue
+			}
+
+			if detail := reconcileInstance(domain, component, controller, uuid); detail != "" {
+				drift[name+"/"+uuid] = detail
+			}
+
+			unlock(domain.Name, name, uuid, "reconciler")
+		}
+	}
+
+	// compare the actual states with the desired states
+	var plan *Plan
+
+	architecture, err := domain.GetArchitecture(domain.Architecture)
+	if err == nil {
+		plan, err = NewPlan(domain, architecture)
+		if err != nil {
+			return nil, err
+		}
+
+		for service, servicePlan := range plan.Services {
+			// services which are being changed by a task are left alone
+			if isBusy(domain, service) {
+				servicePlan.Actions = []*PlanAction{}
+				continue
+			}
+
+			missing := map[string]int{}
+
+			for _, action := range servicePlan.Actions {
+				key := service + "/" + action.Instance
+
+				// instances to be created are identified by their setup
+				if action.Action == PlanActionCreate {
+					key = fmt.Sprintf("%s/%s/%s/%d", service, action.Version, action.State, missing[action.Version+"/"+action.State])
+					missing[action.Version+"/"+action.State]++
+
+					drift[key+"/desired"] = fmt.Sprintf("component '%s': instance of version '%s' in state '%s' is missing", service, action.Version, action.State)
+					continue
+				}
+
+				if action.Reason != "" && action.Current == action.State {
+					drift[key+"/desired"] = fmt.Sprintf("instance '%s' of component '%s': %s", action.Instance, service, action.Reason)
+					continue
+				}
+
+				drift[key+"/desired"] = fmt.Sprintf("instance '%s' of component '%s': actual state '%s' differs from desired state '%s'", action.Instance, service, action.Current, action.State)
+			}
+		}
+	}
+
+	// record newly detected drift
+	events := recordDrift(domain, drift)
+
+	// correct the drift
+	if domain.Reconcile && plan != nil && plan.Changes() > 0 {
+		err = correctDrift(plan)
+		if err != nil {
+			return events, err
+		}
+	}
+
+	// success
+	return events, nil
+}
+
+//------------------------------------------------------------------------------
+
+// reconcileInstance compares the recorded state of an instance with the state
+// reported by its controller. The reported state is recorded as the actual
+// state and a description of the drift is returned.
+func reconcileInstance(domain *model.Domain, component *model.Component, controller ctrl.Controller, uuid string) string {
+	instance, err := component.GetInstance(uuid)
+	if err != nil {
+		return ""
+	}
+
+	configuration, err := model.GetConfiguration(domain.Name, component.Name, uuid)
+	if err != nil {
+		return ""
+	}
+
+	status, _ := controller.Status(configuration)
+	if status == nil || status.InstanceState == instance.State {
+		return ""
+	}
+
+	detail := fmt.Sprintf("instance '%s' of component '%s': recorded state '%s' differs from reported state '%s'", uuid, component.Name, instance.State, status.InstanceState)
+
+	// the reported state is the actual state
+	model.SetInstanceState(instance, status.InstanceState)
+	journalInstance(domain.Name, component, instance)
+
+	return detail
+}
+
+//------------------------------------------------------------------------------
+
+// isBusy determines if a service of a domain is being changed, i.e. if a task
+// of the service has not finished yet while the task tree it belongs to is
+// being executed.
+func isBusy(domain *model.Domain, service string) bool {
+	tasks, _ := domain.ListTasks()
+	for _, uuid := range tasks {
+		task, err := domain.GetTask(uuid)
+		if err != nil || task.Component != service || task.GetStatus() > model.TaskStatusExecuting {
+			continue
+		}
+
+		// determine the root of the task tree
+		root := task
+		if parents := ancestors(task); len(parents) > 0 {
+			root = parents[len(parents)-1]
+		}
+
+		if root.GetStatus() == model.TaskStatusExecuting {
+			return true
+		}
+	}
+	return false
+}
+
+//------------------------------------------------------------------------------
+
+// recordDrift records events for drift which has not been reported before and
+// forgets about drift which has disappeared.
+func recordDrift(domain *model.Domain, drift map[string]string) []*model.Event {
+	reportedDriftLock.Lock()
+	defer reportedDriftLock.Unlock()
+
+	events := []*model.Event{}
+
+	// forget about drift which has disappeared
+	prefix := domain.Name + "/"
+	for key := range reportedDrift {
+		if strings.HasPrefix(key, prefix) {
+			if _, found := drift[strings.TrimPrefix(key, prefix)]; !found {
+				delete(reportedDrift, key)
+			}
+		}
+	}
+
+	// record new drift
+	keys := []string{}
+	for key := range drift {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		detail := drift[key]
+		if reportedDrift[prefix+key] == detail {
+			continue
+		}
+		reportedDrift[prefix+key] = detail
+
+		event := model.NewEvent(domain.Name, "", model.EventTypeDrift, "reconciler")
+		event.Detail = detail
+
+		recordEvent(domain, &event)
+
+		events = append(events, &event)
+	}
+
+	return events
+}
+
+//------------------------------------------------------------------------------
+
+// correctDrift executes the corrective instance tasks of a plan.
+func correctDrift(plan *Plan) error {
+	task, err := NewPlanTask("", plan)
+	if err != nil {
+		return err
+	}
+
+	GetEventChannel() <- model.NewEvent(plan.Domain, task.UUID, model.EventTypeTaskExecution, "")
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,209 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	ctrl "tsai.eu/orchestrator/controller"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// testController manages the instances of the component type "test" in memory.
+type testController struct{}
+
+// testInstances keeps the states reported by the test controller and the
+// transitions which fail.
+var testInstances = struct {
+	sync.Mutex
+	States   map[string]string // reported state per instance
+	Failures map[string]string // failing transition per version
+}{States: map[string]string{}, Failures: map[string]string{}}
+
+var testControllerOnce sync.Once
+
+//------------------------------------------------------------------------------
+
+// registerTestController registers the test controller for the component type
+// "test" with the default state machine.
+func registerTestController(t *testing.T) {
+	testControllerOnce.Do(func() {
+		if err := ctrl.RegisterController("test", testController{}, nil); err != nil {
+			t.Fatalf("unable to register controller: %v", err)
+		}
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// reportState defines the state the test controller reports for an instance.
+func reportState(instance string, state string) {
+	testInstances.Lock()
+	testInstances.States[instance] = state
+	testInstances.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
+// failTransition lets a transition of the instances of a version fail ("" =
+// all transitions succeed).
+func failTransition(version string, method string) {
+	testInstances.Lock()
+	testInstances.Failures[version] = method
+	testInstances.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
+// transition moves an instance into a new state unless the transition fails
+// for its version, in which case the instance is reported to have failed.
+func (c testController) transition(method string, state string, configuration *model.ComponentConfiguration) (*model.ComponentStatus, error) {
+	testInstances.Lock()
+	defer testInstances.Unlock()
+
+	status := model.DeriveComponentStatus(configuration)
+	status.Changed = true
+
+	if testInstances.Failures[configuration.Instances[configuration.Instance].Version] == method {
+		status.InstanceState = model.FailureState
+		testInstances.States[configuration.Instance] = status.InstanceState
+		return status, errors.New(method + " failed")
+	}
+
+	if state != "" {
+		status.InstanceState = state
+	}
+	testInstances.States[configuration.Instance] = status.InstanceState
+
+	return status, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Status reports the state of an instance (the recorded state if no state has
+// been reported).
+func (c testController) Status(configuration *model.ComponentConfiguration) (*model.ComponentStatus, error) {
+	testInstances.Lock()
+	defer testInstances.Unlock()
+
+	status := model.DeriveComponentStatus(configuration)
+	if state, found := testInstances.States[configuration.Instance]; found {
+		status.InstanceState = state
+	}
+
+	return status, nil
+}
+
+// Create creates an instance.
+func (c testController) Create(configuration *model.ComponentConfiguration) (*model.ComponentStatus, error) {
+	return c.transition("create", model.InactiveState, configuration)
+}
+
+// Destroy destroys an instance.
+func (c testController) Destroy(configuration *model.ComponentConfiguration) (*model.ComponentStatus, error) {
+	return c.transition("destroy", model.InitialState, configuration)
+}
+
+// Configure configures an instance.
+func (c testController) Configure(configuration *model.ComponentConfiguration) (*model.ComponentStatus, error) {
+	return c.transition("configure", "", configuration)
+}
+
+// Start starts an instance.
+func (c testController) Start(configuration *model.ComponentConfiguration) (*model.ComponentStatus, error) {
+	return c.transition("start", model.ActiveState, configuration)
+}
+
+// Stop stops an instance.
+func (c testController) Stop(configuration *model.ComponentConfiguration) (*model.ComponentStatus, error) {
+	return c.transition("stop", model.InactiveState, configuration)
+}
+
+// Reset resets an instance after a failure.
+func (c testController) Reset(configuration *model.ComponentConfiguration) (*model.ComponentStatus, error) {
+	return c.transition("reset", model.InitialState, configuration)
+}
+
+//------------------------------------------------------------------------------
+
+// driftDetails collects the details of drift events.
+func driftDetails(events []*model.Event) []string {
+	details := []string{}
+	for _, event := range events {
+		details = append(details, event.Detail)
+	}
+	return details
+}
+
+//------------------------------------------------------------------------------
+
+func TestReconcileDomain(t *testing.T) {
+	registerTestController(t)
+
+	domain := newTestDomain(t, map[string][]string{"app": {}})
+	defer model.GetModel().DeleteDomain(domain.Name)
+
+	instance := addTestInstance(t, domain, "app", model.ActiveState, "app-1")
+
+	// the reported state becomes the actual state
+	reportState(instance.UUID, model.InactiveState)
+
+	events, err := ReconcileDomain(domain)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if details := driftDetails(events); len(details) != 1 || !strings.Contains(details[0], "recorded state 'active' differs from reported state 'inactive'") {
+		t.Errorf("unexpected drift %v", details)
+	}
+	if instance.State != model.InactiveState {
+		t.Errorf("expected state %s, got %s", model.InactiveState, instance.State)
+	}
+
+	// drift is reported once
+	if events, _ := ReconcileDomain(domain); len(events) != 0 {
+		t.Errorf("unexpected drift %v", driftDetails(events))
+	}
+
+	// instances locked by a task are left alone
+	reportState(instance.UUID, model.FailureState)
+
+	if !tryLock(domain.Name, "app", instance.UUID, "task") {
+		t.Fatalf("unable to lock instance")
+	}
+	if events, _ := ReconcileDomain(domain); len(events) != 0 || instance.State != model.InactiveState {
+		t.Errorf("unexpected drift of a locked instance %v", driftDetails(events))
+	}
+	unlock(domain.Name, "app", instance.UUID, "task")
+
+	reportState(instance.UUID, model.InactiveState)
+
+	// the actual state differs from the desired state of the architecture
+	addTestArchitecture(t, domain, "architecture", testSetup{"app", "1.0.0", "active", 1})
+	domain.Architecture = "architecture"
+
+	events, _ = ReconcileDomain(domain)
+	if details := driftDetails(events); len(details) != 1 || !strings.Contains(details[0], "actual state 'inactive' differs from desired state 'active'") {
+		t.Errorf("unexpected drift %v", details)
+	}
+
+	// services which are being changed by a task are left alone
+	task := newTestTask(t, domain, "")
+	task.Component = "app"
+	task.SetStatus(model.TaskStatusExecuting)
+
+	if events, _ := ReconcileDomain(domain); len(events) != 0 {
+		t.Errorf("unexpected drift of a busy service %v", driftDetails(events))
+	}
+
+	// the drift is reported again once the task has finished
+	task.SetStatus(model.TaskStatusCompleted)
+
+	if events, _ := ReconcileDomain(domain); len(events) != 1 {
+		t.Errorf("expected drift, got %v", driftDetails(events))
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,186 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// SwitchRelease directs the endpoint of a component to a new version
+const SwitchRelease string = "release"
+
+// SwitchPromote completes a release in progress
+const SwitchPromote string = "promote"
+
+// SwitchAbort reverts a release in progress
+const SwitchAbort string = "abort"
+
+//------------------------------------------------------------------------------
+
+// NewReleaseTask creates a task which promotes or aborts the release in
+// progress of a component and removes all instances of the discarded version.
+func NewReleaseTask(domain string, component string, operation string) (model.Task, error) {
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return model.Task{}, errors.New("unknown domain")
+	}
+
+	// get component
+	c, err := d.GetComponent(component)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	// determine the version which is retained
+	version, err := retainedVersion(c, operation)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	// create main task
+	task, err := NewSequentialTask(domain, "", []string{})
+	if err != nil {
+		return task, err
+	}
+
+	main, _ := d.GetTask(task.UUID)
+	main.Component = component
+
+	// switch the endpoint
+	switchTask, err := NewSwitchTask(domain, main.UUID, version, operation)
+	if err != nil {
+		return task, err
+	}
+	main.AddSubtask(&switchTask)
+
+	// remove the instances of all other versions
+	initial := model.GetStateMachine(c.Type).Initial
+	removals := []*PlanAction{}
+
+	instances, _ := c.ListInstances()
+	sort.Strings(instances)
+	for _, uuid := range instances {
+		instance, _ := c.GetInstance(uuid)
+		if instance.Version != version {
+			removals = append(removals, &PlanAction{
+				Action:   PlanActionRemove,
+				Version:  instance.Version,
+				Instance: instance.UUID,
+				Current:  instance.State,
+				State:    initial,
+			})
+		}
+	}
+
+	err = addGroupTask(d, main, "", removals)
+	if err != nil {
+		return task, err
+	}
+
+	// success
+	return *main, nil
+}
+
+//------------------------------------------------------------------------------
+
+// retainedVersion determines the version of a component which remains after
+// a release in progress has been promoted or aborted.
+func retainedVersion(component *model.Component, operation string) (string, error) {
+	switch {
+	case component.Canary != "" && operation == SwitchPromote:
+		return component.Canary, nil
+	case component.Canary != "" && operation == SwitchAbort:
+		return component.Active, nil
+	case component.Previous != "" && operation == SwitchPromote:
+		return component.Active, nil
+	case component.Previous != "" && operation == SwitchAbort:
+		return component.Previous, nil
+	case operation != SwitchPromote && operation != SwitchAbort:
+		return "", errors.Errorf("unknown operation: '%s'", operation)
+	}
+
+	return "", errors.New("no release in progress")
+}
+
+//------------------------------------------------------------------------------
+
+// switchRelease directs the endpoint of a component to a new version. A
+// blue/green release switches the endpoint at once and keeps the old version
+// for a rollback, a canary release shares the traffic between both versions.
+func switchRelease(component *model.Component, version string, policy *model.ReleasePolicy) {
+	if (component.Active == version && component.Canary == "") || component.Canary == version {
+		return
+	}
+
+	// determine the version currently in use
+	old := component.Active
+	if old == "" {
+		instances, _ := component.ListInstances()
+		sort.Strings(instances)
+		for _, uuid := range instances {
+			instance, err := component.GetInstance(uuid)
+			if err == nil && instance.Version != version {
+				old = instance.Version
+				break
+			}
+		}
+	}
+
+	// the first release of a component has nothing to switch from
+	if old == "" || old == version {
+		activateVersion(component, version)
+		component.Previous = ""
+		return
+	}
+
+	switch policy.Mode {
+	case model.ReleaseBlueGreen:
+		activateVersion(component, version)
+		component.Previous = old
+	case model.ReleaseCanary:
+		oldEndpoint, _ := component.GetEndpoint(old)
+		newEndpoint, _ := component.GetEndpoint(version)
+
+		component.Active = old
+		component.Canary = version
+		component.Weights = []*model.WeightedEndpoint{
+			{Version: old, Endpoint: oldEndpoint, Weight: 100 - policy.Weight},
+			{Version: version, Endpoint: newEndpoint, Weight: policy.Weight},
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// finishRelease promotes or aborts the release in progress of a component.
+func finishRelease(component *model.Component, operation string) error {
+	version, err := retainedVersion(component, operation)
+	if err != nil {
+		return err
+	}
+
+	activateVersion(component, version)
+	component.Previous = ""
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// activateVersion directs the endpoint of a component to a single version.
+func activateVersion(component *model.Component, version string) {
+	component.Active = version
+	component.Canary = ""
+	component.Weights = nil
+
+	if endpoint, err := component.GetEndpoint(version); err == nil {
+		component.Endpoint = endpoint
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"testing"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+func TestSwitchRelease(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		operation string
+		switched  string // endpoint after the switch
+		weights   int    // number of weighted endpoints after the switch
+		finished  string // endpoint after the operation
+		active    string // active version after the operation
+	}{
+		{"blue/green promote", model.ReleaseBlueGreen, SwitchPromote, "app-2", 0, "app-2", "2.0.0"},
+		{"blue/green abort", model.ReleaseBlueGreen, SwitchAbort, "app-2", 0, "app-1", "1.0.0"},
+		{"canary promote", model.ReleaseCanary, SwitchPromote, "app-1", 2, "app-2", "2.0.0"},
+		{"canary abort", model.ReleaseCanary, SwitchAbort, "app-1", 2, "app-1", "1.0.0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{"app": {}})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			addTestInstance(t, domain, "app", model.ActiveState, "app-1")
+			component, _ := domain.GetComponent("app")
+			component.Endpoint = "app-1"
+			component.AddEndpoint("2.0.0", "app-2")
+
+			if err := finishRelease(component, test.operation); err == nil {
+				t.Errorf("expected an error without a release in progress")
+			}
+
+			policy, _ := model.NewReleasePolicy(test.mode, 10)
+			switchRelease(component, "2.0.0", policy)
+
+			if component.Endpoint != test.switched || len(component.Weights) != test.weights {
+				t.Errorf("unexpected endpoint %s with weights %v after the switch", component.Endpoint, component.Weights)
+			}
+
+			if err := finishRelease(component, test.operation); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if component.Endpoint != test.finished || component.Active != test.active {
+				t.Errorf("expected endpoint %s of version %s, got %s of version %s", test.finished, test.active, component.Endpoint, component.Active)
+			}
+			if component.Previous != "" || component.Canary != "" || component.Weights != nil {
+				t.Errorf("release has not finished: previous %s, canary %s, weights %v", component.Previous, component.Canary, component.Weights)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
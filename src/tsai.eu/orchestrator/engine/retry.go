@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"time"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// DefaultRetryPolicies defines the retry policies per task type which apply if
+// neither the template nor the template variant define a retry policy.
+var DefaultRetryPolicies = map[string]*model.RetryPolicy{
+	"TransitionTask": {
+		MaxAttempts:  1,
+		InitialDelay: 1000,
+		Multiplier:   2.0,
+	},
+}
+
+//------------------------------------------------------------------------------
+
+// determineRetryPolicy determines the retry policy which applies to a task.
+func determineRetryPolicy(task *model.Task) *model.RetryPolicy {
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err == nil {
+		template, err := domain.GetTemplate(task.Component)
+		if err == nil {
+			// a policy of the variant supersedes the policy of the template
+			variant, err := template.GetVariant(task.Version)
+			if err == nil && variant.Retry != nil {
+				return variant.Retry
+			}
+
+			if template.Retry != nil {
+				return template.Retry
+			}
+		}
+	}
+
+	// use the default policy of the task type
+	if policy, found := DefaultRetryPolicies[task.Type]; found {
+		return policy
+	}
+
+	// do not retry at all
+	return &model.RetryPolicy{MaxAttempts: 1}
+}
+
+//------------------------------------------------------------------------------
+
+// retryTask records a failed attempt of a task and schedules another attempt if
+// the retry policy of the task allows. The result indicates if a retry has been
+// scheduled.
+func retryTask(task *model.Task, err error) bool {
+	// record error
+	task.SetError(err)
+
+	// check if another attempt is allowed
+	policy := determineRetryPolicy(task)
+	if task.Attempts >= policy.MaxAttempts || !policy.IsRetryable(err) {
+		return false
+	}
+
+	// retrigger the execution of the task after a delay
+	delay := policy.Delay(task.Attempts)
+	event := model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskExecution, task.UUID)
+
+	time.AfterFunc(delay, func() {
+		GetEventChannel() <- event
+	})
+
+	// success
+	return true
+}
+
+//------------------------------------------------------------------------------
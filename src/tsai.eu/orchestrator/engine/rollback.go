@@ -0,0 +1,178 @@
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// appliedArchitectures keeps a copy of the architecture of each architecture
+// task which is being executed (task uuid -> architecture).
+var appliedArchitectures = map[string]*model.Architecture{}
+var appliedArchitecturesLock sync.Mutex
+
+//------------------------------------------------------------------------------
+
+// rememberArchitecture records the architecture an architecture task applies.
+func rememberArchitecture(task *model.Task, architecture *model.Architecture) {
+	copied := architecture.Copy()
+
+	appliedArchitecturesLock.Lock()
+	appliedArchitectures[task.UUID] = copied
+	appliedArchitecturesLock.Unlock()
+
+	journalArchitecture(task.Domain, task.UUID, copied)
+}
+
+//------------------------------------------------------------------------------
+
+// concludeArchitectureTask records the architecture of an architecture task
+// which has completed as the applied architecture of the domain and initiates
+// the rollback of an architecture task which has failed. Each architecture
+// task is concluded only once.
+func concludeArchitectureTask(task *model.Task) {
+	appliedArchitecturesLock.Lock()
+	architecture, found := appliedArchitectures[task.UUID]
+	delete(appliedArchitectures, task.UUID)
+	appliedArchitecturesLock.Unlock()
+
+	if !found {
+		return
+	}
+	journalArchitecture(task.Domain, task.UUID, nil)
+
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		return
+	}
+
+	switch task.GetStatus() {
+	case model.TaskStatusCompleted:
+		// a rollback restores the applied architecture
+		if task.Compensates != "" {
+			return
+		}
+
+		domain.Applied = architecture
+		journalArchitecture(domain.Name, "", architecture)
+	case model.TaskStatusFailed, model.TaskStatusTimeout:
+		// rollbacks are not rolled back themselves
+		if !domain.Rollback || task.Compensates != "" || task.Rollback != "" || domain.Applied == nil {
+			return
+		}
+
+		go rollbackArchitectureTask(domain, task)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// rollbackArchitectureTask terminates the remaining subtasks of a failed task
+// and starts its rollback once all of them have finished. The changes of the
+// rollback are determined only then, since the subtasks still change the state
+// of the instances until they have finished.
+func rollbackArchitectureTask(domain *model.Domain, task *model.Task) {
+	channel := GetEventChannel()
+
+	// terminate the remaining subtasks
+	for _, uuid := range task.GetSubtasks() {
+		subtask, err := domain.GetTask(uuid)
+		if err == nil && subtask.GetStatus() <= model.TaskStatusExecuting {
+			channel <- model.NewEvent(task.Domain, uuid, model.EventTypeTaskTermination, task.UUID)
+		}
+	}
+
+	// wait for the subtasks to finish
+	for hasRunningTasks(domain, task.GetSubtasks()) {
+		time.Sleep(rollbackInterval)
+	}
+
+	rollback, err := NewRollbackTask(task)
+	if err != nil {
+		return
+	}
+	journalTask(task)
+
+	channel <- model.NewEvent(task.Domain, rollback.UUID, model.EventTypeTaskExecution, task.UUID)
+}
+
+//------------------------------------------------------------------------------
+
+// rollbackInterval defines how often the subtasks of a failed task are checked
+// before its rollback.
+var rollbackInterval = 100 * time.Millisecond
+
+//------------------------------------------------------------------------------
+
+// hasRunningTasks determines if any of the tasks or of their subtasks has not
+// finished yet. Initial tasks are regarded as running only as long as their
+// parent has not finished, since only the parent starts them.
+func hasRunningTasks(domain *model.Domain, uuids []string) bool {
+	for _, uuid := range uuids {
+		task, err := domain.GetTask(uuid)
+		if err != nil {
+			continue
+		}
+
+		switch task.GetStatus() {
+		case model.TaskStatusExecuting:
+			return true
+		case model.TaskStatusInitial:
+			if parent, err := domain.GetTask(task.Parent); err == nil && parent.GetStatus() <= model.TaskStatusExecuting {
+				return true
+			}
+		}
+
+		if hasRunningTasks(domain, task.GetSubtasks()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//------------------------------------------------------------------------------
+
+// NewRollbackTask creates a task which restores the architecture which has been
+// applied successfully before a failed task. The rollback becomes a subtask of
+// the failed task.
+func NewRollbackTask(failed *model.Task) (model.Task, error) {
+	domain, err := model.GetModel().GetDomain(failed.Domain)
+	if err != nil {
+		return model.Task{}, errors.New("unknown domain")
+	}
+
+	if domain.Applied == nil {
+		return model.Task{}, errors.New("no architecture has been applied yet")
+	}
+
+	// determine the required changes
+	plan, err := NewPlan(domain, domain.Applied)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	// a rollback does not wait for approvals
+	plan.Gates = []*PlanGate{}
+
+	task, err := NewPlanTask(failed.UUID, plan)
+	if err != nil {
+		return task, err
+	}
+
+	// link the rollback with the failed task
+	rollback, _ := domain.GetTask(task.UUID)
+	rollback.Compensates = failed.UUID
+
+	failed.SetRollback(rollback.UUID)
+	failed.AddSubtask(rollback)
+
+	// success
+	return *rollback, nil
+}
+
+//------------------------------------------------------------------------------
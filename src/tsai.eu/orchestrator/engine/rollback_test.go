@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"testing"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// startTestDispatcher starts a dispatcher with a worker pool of its own.
+func startTestDispatcher() *Dispatcher {
+	dispatcher := Dispatcher{
+		Model:   model.GetModel(),
+		Channel: GetEventChannel(),
+		Pool:    NewWorkerPool(4, 0, 0),
+	}
+	go dispatcher.Run()
+
+	return &dispatcher
+}
+
+//------------------------------------------------------------------------------
+
+// stopTestDispatcher stops a dispatcher once its worker pool is idle.
+func stopTestDispatcher(t *testing.T, dispatcher *Dispatcher) {
+	waitIdle(t, dispatcher)
+
+	dispatcher.Channel <- model.Event{}
+}
+
+//------------------------------------------------------------------------------
+
+// waitIdle waits until the worker pool of a dispatcher has been idle for a
+// while, since handlers may still emit events after a task has finished.
+func waitIdle(t *testing.T, dispatcher *Dispatcher) {
+	idle := 0
+	waitFor(t, "idle worker pool", func() bool {
+		statistics := dispatcher.Pool.Statistics()
+		if statistics.Running > 0 || statistics.Queued > 0 {
+			idle = 0
+			return false
+		}
+		idle++
+		return idle > 10
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// executeTestTask executes a task and waits until it has finished and all
+// resulting handlers have returned.
+func executeTestTask(t *testing.T, dispatcher *Dispatcher, domain *model.Domain, uuid string) *model.Task {
+	task, err := domain.GetTask(uuid)
+	if err != nil {
+		t.Fatalf("unknown task: %v", err)
+	}
+
+	dispatcher.Channel <- model.NewEvent(domain.Name, uuid, model.EventTypeTaskExecution, "")
+
+	waitFor(t, "task to finish", func() bool { return task.GetStatus() > model.TaskStatusExecuting })
+	waitIdle(t, dispatcher)
+
+	return task
+}
+
+//------------------------------------------------------------------------------
+
+func TestRollbackArchitectureTask(t *testing.T) {
+	registerTestController(t)
+
+	domain := newTestDomain(t, map[string][]string{"app": {}})
+	defer model.GetModel().DeleteDomain(domain.Name)
+
+	template, _ := domain.GetTemplate("app")
+	variant, _ := model.NewVariant("2.0.0", "app-configuration-2")
+	template.AddVariant(variant)
+
+	domain.Rollback = true
+
+	dispatcher := startTestDispatcher()
+	defer stopTestDispatcher(t, dispatcher)
+
+	// the architecture which has been applied successfully
+	applied := addTestArchitecture(t, domain, "applied", testSetup{"app", "1.0.0", "active", 1})
+
+	task, err := NewArchitectureTask(domain.Name, "", applied)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if executeTestTask(t, dispatcher, domain, task.UUID).GetStatus() != model.TaskStatusCompleted || domain.Applied == nil {
+		t.Fatalf("architecture has not been applied")
+	}
+
+	// an upgrade which fails is rolled back
+	upgrade := addTestArchitecture(t, domain, "upgrade", testSetup{"app", "2.0.0", "active", 1})
+
+	failTransition("2.0.0", "create")
+	defer failTransition("2.0.0", "")
+
+	task, err = NewArchitectureTask(domain.Name, "", upgrade)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	failed := executeTestTask(t, dispatcher, domain, task.UUID)
+	if failed.GetStatus() != model.TaskStatusFailed {
+		t.Fatalf("expected status %v, got %v", model.TaskStatusFailed, failed.GetStatus())
+	}
+
+	waitFor(t, "rollback", func() bool {
+		rollback, err := domain.GetTask(failed.Snapshot().Rollback)
+		return err == nil && rollback.GetStatus() > model.TaskStatusExecuting
+	})
+	waitIdle(t, dispatcher)
+
+	rollback, _ := domain.GetTask(failed.Snapshot().Rollback)
+	if rollback.GetStatus() != model.TaskStatusCompleted || rollback.Compensates != failed.UUID {
+		t.Errorf("unexpected rollback %v", rollback.Snapshot())
+	}
+
+	// the rollback starts after all other subtasks have finished
+	for _, uuid := range failed.GetSubtasks() {
+		subtask, _ := domain.GetTask(uuid)
+		if uuid != rollback.UUID && subtask.GetFinished() > rollback.GetStarted() {
+			t.Errorf("subtask %s has finished after the start of the rollback", uuid)
+		}
+	}
+
+	// only the instance of the applied architecture remains
+	component, _ := domain.GetComponent("app")
+	instances, _ := component.ListInstances()
+	for _, uuid := range instances {
+		instance, _ := component.GetInstance(uuid)
+		if instance.Version != "1.0.0" || instance.State != model.ActiveState {
+			t.Errorf("unexpected instance of version %s in state %s", instance.Version, instance.State)
+		}
+	}
+	if domain.Applied.Name != applied.Name {
+		t.Errorf("expected applied architecture %s, got %s", applied.Name, domain.Applied.Name)
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// schedulerLock serializes the evaluation of the schedules.
+var schedulerLock sync.Mutex
+
+//------------------------------------------------------------------------------
+
+// StartScheduler periodically triggers the scheduled architecture executions
+// of all domains of the model.
+func StartScheduler(m *model.Model, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for now := range ticker.C {
+			domains, _ := m.ListDomains()
+			for _, name := range domains {
+				domain, err := m.GetDomain(name)
+				if err != nil {
+					continue
+				}
+
+				RunSchedules(domain, now)
+			}
+		}
+	}()
+}
+
+//------------------------------------------------------------------------------
+
+// RunSchedules triggers the schedules of a domain which are due at a point in
+// time as well as queued runs whose previous run has finished.
+func RunSchedules(domain *model.Domain, now time.Time) {
+	schedulerLock.Lock()
+	defer schedulerLock.Unlock()
+
+	schedules, _ := domain.ListSchedules()
+	sort.Strings(schedules)
+	for _, uuid := range schedules {
+		schedule, err := domain.GetSchedule(uuid)
+		if err != nil {
+			continue
+		}
+
+		// start a queued run once the previous run has finished
+		if schedule.Pending && !isRunning(domain, schedule) {
+			schedule.Pending = false
+			startScheduledRun(domain, schedule, now)
+		}
+
+		// check if the schedule is due
+		if schedule.Next == 0 || now.UnixNano() < schedule.Next {
+			continue
+		}
+		schedule.Advance(now)
+
+		// the previous run is still executing
+		if isRunning(domain, schedule) {
+			if schedule.Policy == model.SchedulePolicyQueue {
+				schedule.Pending = true
+				schedule.AddRun(&model.ScheduledRun{Time: now.UnixNano(), Status: model.ScheduledRunQueued})
+			} else {
+				schedule.AddRun(&model.ScheduledRun{Time: now.UnixNano(), Status: model.ScheduledRunSkipped})
+			}
+			continue
+		}
+
+		startScheduledRun(domain, schedule, now)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// isRunning determines if the latest run of a schedule is still executing.
+func isRunning(domain *model.Domain, schedule *model.Schedule) bool {
+	run := schedule.LastRun()
+	if run == nil {
+		return false
+	}
+
+	task, err := domain.GetTask(run.Task)
+	if err != nil {
+		return false
+	}
+
+	return task.GetStatus() <= model.TaskStatusExecuting
+}
+
+//------------------------------------------------------------------------------
+
+// startScheduledRun creates and triggers an architecture task for a schedule
+// and records the run in the history of the schedule.
+func startScheduledRun(domain *model.Domain, schedule *model.Schedule, now time.Time) {
+	run := model.ScheduledRun{Time: now.UnixNano(), Status: model.ScheduledRunStarted}
+
+	task, err := newScheduledTask(domain, schedule)
+	if err != nil {
+		run.Status = model.ScheduledRunFailed
+		run.Error = err.Error()
+	} else {
+		run.Task = task.UUID
+	}
+
+	schedule.AddRun(&run)
+
+	// trigger the task
+	if err == nil {
+		GetEventChannel() <- model.NewEvent(domain.Name, task.UUID, model.EventTypeTaskExecution, schedule.UUID)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// newScheduledTask creates the architecture task of a schedule.
+func newScheduledTask(domain *model.Domain, schedule *model.Schedule) (model.Task, error) {
+	architecture, err := domain.GetArchitecture(schedule.Architecture)
+	if err != nil {
+		return model.Task{}, errors.Errorf("unknown architecture: '%s'", schedule.Architecture)
+	}
+
+	return NewArchitectureTask(domain.Name, "", architecture)
+}
+
+//------------------------------------------------------------------------------
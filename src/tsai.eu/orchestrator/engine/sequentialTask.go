@@ -28,11 +28,7 @@ func NewSequentialTask(domain string, parent string, subtasks []string) (model.T
 	task.Subtasks = subtasks
 
 	// add handlers
-	task.SetExecute(ExecuteSequentialTask)
-	task.SetTerminate(TerminateTask)
-	task.SetFailed(FailedTask)
-	task.SetTimeout(TimeoutTask)
-	task.SetCompleted(CompletedTask)
+	BindHandlers(&task)
 
 	// get domain
 	d, err := model.GetModel().GetDomain(domain)
@@ -79,10 +75,16 @@ func ExecuteSequentialTask(task *model.Task) {
 		return
 	}
 
+	// initialize if needed
+	if status == model.TaskStatusInitial {
+		// start the execution
+		startTask(task)
+	}
+
 	// check if the task has finished
 	if task.Phase >= len(task.Subtasks) {
 		// update status
-		task.Status = model.TaskStatusCompleted
+		task.SetStatus(model.TaskStatusCompleted)
 
 		// inform parent
 		if task.Parent != "" {
@@ -95,7 +97,12 @@ func ExecuteSequentialTask(task *model.Task) {
 
 	// check status of current subtask
 	domain, _ := model.GetModel().GetDomain(task.Domain)
-	subtask, _ := domain.GetTask(task.Subtasks[task.Phase])
+	subtask, err := domain.GetTask(task.Subtasks[task.Phase])
+	if err != nil {
+		task.SetError(err)
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+		return
+	}
 
 	switch subtask.GetStatus() {
 	// trigger subtask which may not have started yet
@@ -112,7 +119,7 @@ func ExecuteSequentialTask(task *model.Task) {
 		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskExecution, task.UUID)
 	// check if subtask has failed
 	case model.TaskStatusFailed:
-		task.Status = model.TaskStatusFailed
+		task.SetStatus(model.TaskStatusFailed)
 
 		// inform parent
 		if task.Parent != "" {
@@ -120,7 +127,7 @@ func ExecuteSequentialTask(task *model.Task) {
 		}
 	// check if subtask has run into a timeout
 	case model.TaskStatusTimeout:
-		task.Status = model.TaskStatusTimeout
+		task.SetStatus(model.TaskStatusTimeout)
 
 		// inform parent
 		if task.Parent != "" {
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"errors"
+	"sort"
 
 	"github.com/google/uuid"
 	"tsai.eu/orchestrator/model"
@@ -29,7 +30,8 @@ type StateSetup struct {
 
 //------------------------------------------------------------------------------
 
-func determineCurrentSetup(domain string, service string) ServiceSetup {
+// determineCurrentSetup determines the setup of the current instances of a service.
+func determineCurrentSetup(domain *model.Domain, service string) ServiceSetup {
 	// create ServiceSetup
 	serviceSetup := ServiceSetup{
 		Name:     service,
@@ -37,9 +39,12 @@ func determineCurrentSetup(domain string, service string) ServiceSetup {
 	}
 
 	// loop over all instances of a component/service
-	d, _ := model.GetModel().GetDomain(domain) // domain
-	c, _ := d.GetComponent(service)            // component
-	l, _ := c.ListInstances()                  // list of instances
+	c, err := domain.GetComponent(service) // component
+	if err != nil {
+		// services without component have no instances
+		return serviceSetup
+	}
+	l, _ := c.ListInstances() // list of instances
 	for n := range l {
 		u := l[n]                // uuid
 		i, _ := c.GetInstance(u) // instance
@@ -51,6 +56,7 @@ func determineCurrentSetup(domain string, service string) ServiceSetup {
 				Version: i.Version,
 				States:  map[string]StateSetup{},
 			}
+			serviceSetup.Versions[i.Version] = versionSetup
 		}
 
 		// check if state exists
@@ -60,6 +66,7 @@ func determineCurrentSetup(domain string, service string) ServiceSetup {
 				State:     i.State,
 				Instances: map[string]string{},
 			}
+			versionSetup.States[i.State] = stateSetup
 		}
 
 		// add instance
@@ -70,18 +77,21 @@ func determineCurrentSetup(domain string, service string) ServiceSetup {
 	return serviceSetup
 }
 
-func determineTargetSetup(domain string, architecture string, service string) ServiceSetup {
+// determineTargetSetup determines the setup of the instances of a service defined by an architecture.
+func determineTargetSetup(architecture *model.Architecture, service string) ServiceSetup {
 	// create ServiceSetup
 	serviceSetup := ServiceSetup{
 		Name:     service,
 		Versions: map[string]VersionSetup{},
 	}
 
-	// loop over all instances of a component/service
-	d, _ := model.GetModel().GetDomain(domain) // domain
-	a, _ := d.GetArchitecture(architecture)    // architecture
-	s, _ := a.GetService(service)              // service
-	l, _ := s.ListSetups()                     // list of setups
+	// loop over all setups of the service
+	s, err := architecture.GetService(service) // service
+	if err != nil {
+		// services which are not part of the architecture have no instances
+		return serviceSetup
+	}
+	l, _ := s.ListSetups() // list of setups
 	for i := range l {
 		n := l[i]             // setup name
 		t, _ := s.GetSetup(n) // setup
@@ -93,6 +103,7 @@ func determineTargetSetup(domain string, architecture string, service string) Se
 				Version: t.Version,
 				States:  map[string]StateSetup{},
 			}
+			serviceSetup.Versions[t.Version] = versionSetup
 		}
 
 		// check if state exists
@@ -102,6 +113,7 @@ func determineTargetSetup(domain string, architecture string, service string) Se
 				State:     t.State,
 				Instances: map[string]string{},
 			}
+			versionSetup.States[t.State] = stateSetup
 		}
 
 		// add instances
@@ -115,12 +127,49 @@ func determineTargetSetup(domain string, architecture string, service string) Se
 	return serviceSetup
 }
 
-func determineTasks(domain string, architecture string, service string) ([]model.Task, []model.Task, []model.Task) {
-	targetSetup := determineTargetSetup(domain, architecture, service)
+// determineReconfiguration determines why an existing instance of a service
+// needs to be reconfigured ("" = no reconfiguration required).
+func determineReconfiguration(domain *model.Domain, service string, uuid string) string {
+	component, err := domain.GetComponent(service)
+	if err != nil {
+		return ""
+	}
+
+	instance, err := component.GetInstance(uuid)
+	if err != nil || instance.State == model.GetStateMachine(component.Type).Initial {
+		return ""
+	}
+
+	return reconfigurationReason(domain, component, instance)
+}
+
+// planService determines the changes of the instances required to move a
+// service towards the setup defined by an architecture.
+func planService(domain *model.Domain, architecture *model.Architecture, service string) *ServicePlan {
+	targetSetup := determineTargetSetup(architecture, service)
 	currentSetup := determineCurrentSetup(domain, service)
-	updateTasks := []model.Task{}
-	createTasks := []model.Task{}
-	removeTasks := []model.Task{}
+	servicePlan := ServicePlan{
+		Service: service,
+		Actions: []*PlanAction{},
+	}
+
+	// adopt the update strategy and the release policy of the service
+	if s, err := architecture.GetService(service); err == nil {
+		if s.Strategy != nil {
+			strategy := *s.Strategy
+			servicePlan.Strategy = &strategy
+		}
+
+		// a release applies to a single version of the service
+		if s.Release != nil && len(targetSetup.Versions) == 1 {
+			release := *s.Release
+			servicePlan.Release = &release
+
+			for version := range targetSetup.Versions {
+				servicePlan.Version = version
+			}
+		}
+	}
 
 	// determine all unchanged instances
 	for _, targetVersionSetup := range targetSetup.Versions {
@@ -139,6 +188,18 @@ func determineTasks(domain string, architecture string, service string) ([]model
 				}
 
 				for currentInstance := range currentStateSetup.Instances {
+					// reconfigure the instance if its configuration has changed
+					if reason := determineReconfiguration(domain, service, currentInstance); reason != "" {
+						servicePlan.Actions = append(servicePlan.Actions, &PlanAction{
+							Action:   PlanActionUpdate,
+							Version:  targetVersionSetup.Version,
+							Instance: currentInstance,
+							Current:  targetStateSetup.State,
+							State:    targetStateSetup.State,
+							Reason:   reason,
+						})
+					}
+
 					// instance has been found - now remove instances from the setup
 					delete(targetStateSetup.Instances, targetInstance)
 					delete(currentStateSetup.Instances, currentInstance)
@@ -159,34 +220,46 @@ func determineTasks(domain string, architecture string, service string) ([]model
 					continue
 				}
 
-				for _, currentStateSetup := range currentVersionSetup.States {
+			search:
+				for currentState, currentStateSetup := range currentVersionSetup.States {
 					for currentInstance := range currentStateSetup.Instances {
-						// create new update task
-						updateTask, _ := NewInstanceTask(domain, "TODO: unknown", architecture, service, targetVersion, currentInstance, targetState)
-
-						// append new task to set of update tasks
-						updateTasks = append(updateTasks, updateTask)
+						// transition the current instance to the target state
+						// (applying a changed configuration on the way)
+						servicePlan.Actions = append(servicePlan.Actions, &PlanAction{
+							Action:   PlanActionUpdate,
+							Version:  targetVersion,
+							Instance: currentInstance,
+							Current:  currentState,
+							State:    targetState,
+							Reason:   determineReconfiguration(domain, service, currentInstance),
+						})
 
 						// instance has been found - now remove instances from the setup
 						delete(targetStateSetup.Instances, targetInstance)
 						delete(currentStateSetup.Instances, currentInstance)
-						break
+						break search
 					}
-
 				}
 			}
 		}
 	}
 
 	// all leftover current instances need to be removed
+	initial := model.DefaultStateMachine().Initial
+	if component, err := domain.GetComponent(service); err == nil {
+		initial = model.GetStateMachine(component.Type).Initial
+	}
+
 	for currentVersion, currentVersionSetup := range currentSetup.Versions {
-		for _, currentStateSetup := range currentVersionSetup.States {
+		for currentState, currentStateSetup := range currentVersionSetup.States {
 			for currentInstance := range currentStateSetup.Instances {
-				// create new remove task
-				removeTask, _ := NewInstanceTask(domain, "TODO: unknown", architecture, service, currentVersion, currentInstance, "initial")
-
-				// append new task to set of remove tasks
-				removeTasks = append(removeTasks, removeTask)
+				servicePlan.Actions = append(servicePlan.Actions, &PlanAction{
+					Action:   PlanActionRemove,
+					Version:  currentVersion,
+					Instance: currentInstance,
+					Current:  currentState,
+					State:    initial,
+				})
 			}
 		}
 	}
@@ -195,30 +268,55 @@ func determineTasks(domain string, architecture string, service string) ([]model
 	for targetVersion, targetVersionSetup := range targetSetup.Versions {
 		for targetState, targetStateSetup := range targetVersionSetup.States {
 			for targetInstance := range targetStateSetup.Instances {
-				// create new create task
-				createTask, _ := NewInstanceTask(domain, "TODO: unknown", architecture, service, targetVersion, targetInstance, targetState)
+				servicePlan.Actions = append(servicePlan.Actions, &PlanAction{
+					Action:   PlanActionCreate,
+					Version:  targetVersion,
+					Instance: targetInstance,
+					Current:  initial,
+					State:    targetState,
+				})
+			}
+		}
+	}
 
-				// append new task to set of create tasks
-				createTasks = append(createTasks, createTask)
+	// instances of other versions are kept until the release is promoted
+	if servicePlan.Release != nil {
+		actions := []*PlanAction{}
+		for _, action := range servicePlan.Actions {
+			if action.Action != PlanActionRemove || action.Version == servicePlan.Version {
+				actions = append(actions, action)
 			}
 		}
+		servicePlan.Actions = actions
 	}
 
+	// order the actions for a reproducible presentation
+	sort.SliceStable(servicePlan.Actions, func(i, j int) bool {
+		a, b := servicePlan.Actions[i], servicePlan.Actions[j]
+		if a.Action != b.Action {
+			return a.Action > b.Action
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Instance < b.Instance
+	})
+
 	// success
-	return updateTasks, createTasks, removeTasks
+	return &servicePlan
 }
 
 //------------------------------------------------------------------------------
 
-// NewServiceTask creates a new task
-func NewServiceTask(domain string, parent string, architecture string, component string) (model.Task, error) {
+// NewServiceTask creates a new task which executes the changes of a service plan
+func NewServiceTask(domain string, parent string, architecture string, servicePlan *ServicePlan) (model.Task, error) {
 	var task model.Task
 
 	// TODO: check parameters if context exists
 	task.Type = "ServiceTask"
 	task.Domain = domain
 	task.Architecture = architecture
-	task.Component = component
+	task.Component = servicePlan.Service
 	task.Version = ""
 	task.Instance = ""
 	task.State = ""
@@ -229,11 +327,7 @@ func NewServiceTask(domain string, parent string, architecture string, component
 	task.Subtasks = []string{}
 
 	// add handlers
-	task.SetExecute(ExecuteServiceTask)
-	task.SetTerminate(TerminateTask)
-	task.SetFailed(FailedTask)
-	task.SetTimeout(TimeoutTask)
-	task.SetCompleted(CompletedTask)
+	BindHandlers(&task)
 
 	// get domain
 	d, err := model.GetModel().GetDomain(domain)
@@ -241,18 +335,263 @@ func NewServiceTask(domain string, parent string, architecture string, component
 		return task, errors.New("unknown domain")
 	}
 
+	// check the release policy and the update strategy
+	strategy := servicePlan.Strategy
+	switch {
+	case servicePlan.Release != nil:
+		err = servicePlan.Release.Validate()
+	case strategy != nil:
+		err = strategy.Validate()
+	}
+	if err != nil {
+		return task, err
+	}
+
 	// add task to domain
 	err = d.AddTask(&task)
 	if err != nil {
 		return task, err
 	}
 
+	// create the subtasks according to the update strategy
+	switch {
+	case servicePlan.Release != nil:
+		err = newReleaseServiceTasks(d, &task, architecture, servicePlan)
+	case strategy == nil:
+		err = newParallelServiceTasks(d, &task, architecture, servicePlan)
+	case strategy.Type == model.StrategyRecreate:
+		err = newRecreateServiceTasks(d, &task, architecture, servicePlan)
+	case strategy.Type == model.StrategyRolling:
+		err = newRollingServiceTasks(d, &task, architecture, servicePlan)
+	}
+	if err != nil {
+		return task, err
+	}
+
 	// success
 	return task, nil
 }
 
 //------------------------------------------------------------------------------
 
+// newParallelServiceTasks executes all changes of a service at once.
+func newParallelServiceTasks(d *model.Domain, task *model.Task, architecture string, servicePlan *ServicePlan) error {
+	mainTask, err := NewParallelTask(d.Name, task.UUID, []string{})
+	if err != nil {
+		return err
+	}
+	task.AddSubtask(&mainTask)
+
+	// create task groups (update, create, remove)
+	main, _ := d.GetTask(mainTask.UUID)
+	for _, action := range []string{PlanActionUpdate, PlanActionCreate, PlanActionRemove} {
+		err = addGroupTask(d, main, architecture, filterActions(servicePlan.Actions, action))
+		if err != nil {
+			return err
+		}
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// newRecreateServiceTasks removes all old instances before the remaining
+// instances are updated and new instances are created.
+func newRecreateServiceTasks(d *model.Domain, task *model.Task, architecture string, servicePlan *ServicePlan) error {
+	mainTask, err := NewSequentialTask(d.Name, task.UUID, []string{})
+	if err != nil {
+		return err
+	}
+	task.AddSubtask(&mainTask)
+
+	// create task groups (remove, update, create)
+	main, _ := d.GetTask(mainTask.UUID)
+	for _, action := range []string{PlanActionRemove, PlanActionUpdate, PlanActionCreate} {
+		err = addGroupTask(d, main, architecture, filterActions(servicePlan.Actions, action))
+		if err != nil {
+			return err
+		}
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// newRollingServiceTasks replaces the instances of a service in batches.
+// Within a batch at most maxSurge new instances are created before old
+// instances are removed and at most maxUnavailable old instances are removed
+// before new instances have been created.
+func newRollingServiceTasks(d *model.Domain, task *model.Task, architecture string, servicePlan *ServicePlan) error {
+	strategy := servicePlan.Strategy
+	size := strategy.Batch()
+
+	mainTask, err := NewSequentialTask(d.Name, task.UUID, []string{})
+	if err != nil {
+		return err
+	}
+	task.AddSubtask(&mainTask)
+
+	main, _ := d.GetTask(mainTask.UUID)
+
+	// pause between two batches
+	batches := 0
+	pause := func() error {
+		batches++
+		if batches == 1 || strategy.Pause == 0 {
+			return nil
+		}
+
+		waitTask, err := NewWaitTask(d.Name, main.UUID, strategy.Pause)
+		if err != nil {
+			return err
+		}
+		main.AddSubtask(&waitTask)
+
+		return nil
+	}
+
+	// update existing instances in batches
+	updates := filterActions(servicePlan.Actions, PlanActionUpdate)
+	for len(updates) > 0 {
+		n := minimum(size, len(updates))
+
+		if err = pause(); err != nil {
+			return err
+		}
+		if err = addGroupTask(d, main, architecture, updates[:n]); err != nil {
+			return err
+		}
+
+		updates = updates[n:]
+	}
+
+	// replace old instances by new instances in batches
+	creates := filterActions(servicePlan.Actions, PlanActionCreate)
+	removes := filterActions(servicePlan.Actions, PlanActionRemove)
+	for len(creates) > 0 || len(removes) > 0 {
+		nc := minimum(size, len(creates))
+		nr := minimum(size, len(removes))
+		sc := minimum(strategy.MaxSurge, nc)
+		sr := minimum(strategy.MaxUnavailable, nr)
+
+		if err = pause(); err != nil {
+			return err
+		}
+
+		batchTask, err := NewSequentialTask(d.Name, main.UUID, []string{})
+		if err != nil {
+			return err
+		}
+		main.AddSubtask(&batchTask)
+
+		batch, _ := d.GetTask(batchTask.UUID)
+
+		// surge and unavailable instances first
+		first := append(append([]*PlanAction{}, creates[:sc]...), removes[:sr]...)
+		if err = addGroupTask(d, batch, architecture, first); err != nil {
+			return err
+		}
+
+		// remainder of the batch afterwards
+		second := append(append([]*PlanAction{}, creates[sc:nc]...), removes[sr:nr]...)
+		if err = addGroupTask(d, batch, architecture, second); err != nil {
+			return err
+		}
+
+		creates = creates[nc:]
+		removes = removes[nr:]
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// newReleaseServiceTasks brings up the instances of the new version before the
+// endpoint of the component is switched. Instances of other versions are kept
+// until the release is promoted.
+func newReleaseServiceTasks(d *model.Domain, task *model.Task, architecture string, servicePlan *ServicePlan) error {
+	mainTask, err := NewSequentialTask(d.Name, task.UUID, []string{})
+	if err != nil {
+		return err
+	}
+	task.AddSubtask(&mainTask)
+
+	main, _ := d.GetTask(mainTask.UUID)
+
+	// bring up the new version
+	for _, action := range []string{PlanActionUpdate, PlanActionCreate} {
+		err = addGroupTask(d, main, architecture, filterActions(servicePlan.Actions, action))
+		if err != nil {
+			return err
+		}
+	}
+
+	// switch the endpoint
+	switchTask, err := NewSwitchTask(d.Name, main.UUID, servicePlan.Version, SwitchRelease)
+	if err != nil {
+		return err
+	}
+	main.AddSubtask(&switchTask)
+
+	// remove surplus instances of the new version
+	return addGroupTask(d, main, architecture, filterActions(servicePlan.Actions, PlanActionRemove))
+}
+
+//------------------------------------------------------------------------------
+
+// addGroupTask attaches a parallel task with an instance task for each change to a task.
+func addGroupTask(d *model.Domain, parent *model.Task, architecture string, actions []*PlanAction) error {
+	groupTask, err := NewParallelTask(d.Name, parent.UUID, []string{})
+	if err != nil {
+		return err
+	}
+	parent.AddSubtask(&groupTask)
+
+	group, _ := d.GetTask(groupTask.UUID)
+	for _, change := range actions {
+		subtask, err := NewInstanceTask(d.Name, group.UUID, architecture, parent.Component, change.Version, change.Instance, change.State)
+		if err != nil {
+			return errors.New("unable to create subtask for a required instance")
+		}
+
+		group.AddSubtask(&subtask)
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// minimum determines the smaller of two numbers.
+func minimum(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+//------------------------------------------------------------------------------
+
+// filterActions selects the actions of a specific type.
+func filterActions(actions []*PlanAction, action string) []*PlanAction {
+	result := []*PlanAction{}
+	for _, change := range actions {
+		if change.Action == action {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+//------------------------------------------------------------------------------
+
 // ExecuteServiceTask is the main task execution routine.
 func ExecuteServiceTask(task *model.Task) {
 	// get event channel
@@ -265,56 +604,82 @@ func ExecuteServiceTask(task *model.Task) {
 		return
 	}
 
+	// get domain
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+		return
+	}
+
 	// initialize if needed
 	if status == model.TaskStatusInitial {
-		// update status
-		task.Status = model.TaskStatusExecuting
-
-		// determine required subtasks
-		updateTasks, createTasks, removeTasks := determineTasks(task.Domain, task.Architecture, task.Component)
+		// start the execution
+		startTask(task)
+
+		// ensure the component of the service exists
+		err = provideComponent(domain, task.Component)
+		if err != nil {
+			channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+			return
+		}
 
-		// add tasks to domain
+		// trigger execution of main subtask
+		channel <- model.NewEvent(task.Domain, task.Subtasks[0], model.EventTypeTaskExecution, task.UUID)
 
-		// create task groups
-		mainTask, _ := NewParallelTask(task.Domain, task.UUID, []string{})
-		task.AddSubtask(&mainTask)
+		// success
+		return
+	}
 
-		// add update subtasks
-		updateTask, _ := NewParallelTask(task.Domain, mainTask.GetUUID(), []string{})
-		mainTask.AddSubtask(&updateTask)
-		for _, s := range updateTasks {
-			subTask, _ := NewInstanceTask(s.Domain, mainTask.GetUUID(), task.Architecture, s.Component, s.Version, s.Instance, s.State)
+	// check the status of the main subtask
+	if len(task.Subtasks) == 0 {
+		return
+	}
 
-			updateTask.AddSubtask(&subTask)
-		}
+	subtask, err := domain.GetTask(task.Subtasks[0])
+	if err != nil {
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+		return
+	}
 
-		// add create subtasks
-		createTask, _ := NewParallelTask(task.Domain, mainTask.GetUUID(), []string{})
-		mainTask.AddSubtask(&createTask)
-		for _, s := range createTasks {
-			subTask, _ := NewInstanceTask(s.Domain, mainTask.GetUUID(), task.Architecture, s.Component, s.Version, s.Instance, s.State)
+	switch subtask.GetStatus() {
+	// signal completion to the parent
+	case model.TaskStatusCompleted:
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskCompletion, task.UUID)
+	// signal failure to the parent
+	case model.TaskStatusFailed, model.TaskStatusTerminated:
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+	// signal timeout to the parent
+	case model.TaskStatusTimeout:
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskTimeout, task.UUID)
+	}
 
-			createTask.AddSubtask(&subTask)
-		}
+	// success
+	return
+}
 
-		// add remove subtasks
-		removeTask, _ := NewParallelTask(task.Domain, mainTask.GetUUID(), []string{})
-		mainTask.AddSubtask(&removeTask)
-		for _, s := range removeTasks {
-			subTask, _ := NewInstanceTask(s.Domain, mainTask.GetUUID(), task.Architecture, s.Component, s.Version, s.Instance, s.State)
+//------------------------------------------------------------------------------
 
-			removeTask.AddSubtask(&subTask)
-		}
+// provideComponent creates the component of a service if it does not exist yet.
+func provideComponent(domain *model.Domain, name string) error {
+	// check if component already exists
+	if _, err := domain.GetComponent(name); err == nil {
+		return nil
+	}
 
-		// trigger execution of main subtask
-		channel <- model.NewEvent(task.Domain, mainTask.GetUUID(), model.EventTypeTaskExecution, task.UUID)
+	// determine the type of the component from its template
+	template, err := domain.GetTemplate(name)
+	if err != nil {
+		return err
+	}
 
-		// success
-		return
+	// create the component
+	component, err := model.NewComponent(name, template.Type)
+	if err != nil {
+		return err
 	}
 
 	// success
-	return
+	return domain.AddComponent(component)
 }
 
 //------------------------------------------------------------------------------
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// describeTasks summarises the structure of the subtasks of a task: groups of
+// instance tasks are shown as [...] with a letter per action (c/r/u), batches
+// as (...) and pauses as "wait".
+func describeTasks(domain *model.Domain, uuids []string) string {
+	descriptions := []string{}
+
+	for _, uuid := range uuids {
+		task, err := domain.GetTask(uuid)
+		if err != nil {
+			continue
+		}
+
+		switch task.Type {
+		case "ParallelTask":
+			actions := []string{}
+			for _, subtask := range task.GetSubtasks() {
+				instanceTask, _ := domain.GetTask(subtask)
+				actions = append(actions, instanceTask.Instance[:1])
+			}
+			sort.Strings(actions)
+			descriptions = append(descriptions, "["+strings.Join(actions, "")+"]")
+		case "SequentialTask":
+			descriptions = append(descriptions, "("+strings.Replace(describeTasks(domain, task.GetSubtasks()), " ", "", -1)+")")
+		case "WaitTask":
+			descriptions = append(descriptions, "wait")
+		}
+	}
+
+	return strings.Join(descriptions, " ")
+}
+
+//------------------------------------------------------------------------------
+
+func TestRollingServiceTasks(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxSurge       int
+		maxUnavailable int
+		batchSize      int
+		pause          int
+		updates        int
+		creates        int
+		removes        int
+		expected       string
+	}{
+		{"surge", 1, 0, 0, 0, 0, 2, 2, "([c][r]) ([c][r])"},
+		{"unavailable", 0, 1, 0, 0, 0, 2, 2, "([r][c]) ([r][c])"},
+		{"surge and unavailable", 1, 1, 0, 0, 0, 3, 3, "([cr][cr]) ([cr][])"},
+		{"batch size", 1, 0, 3, 0, 0, 3, 3, "([c][ccrrr])"},
+		{"scale up", 1, 0, 0, 0, 0, 2, 0, "([c][]) ([c][])"},
+		{"scale down", 0, 1, 0, 0, 0, 0, 2, "([r][]) ([r][])"},
+		{"updates", 1, 1, 0, 0, 3, 0, 0, "[uu] [u]"},
+		{"pause", 1, 0, 0, 100, 1, 1, 1, "[u] wait ([c][r])"},
+		{"nothing to do", 1, 0, 0, 100, 0, 0, 0, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{"app": {}})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			strategy, _ := model.NewUpdateStrategy(model.StrategyRolling)
+			strategy.MaxSurge = test.maxSurge
+			strategy.MaxUnavailable = test.maxUnavailable
+			strategy.BatchSize = test.batchSize
+			strategy.Pause = test.pause
+
+			servicePlan := ServicePlan{Service: "app", Strategy: strategy, Actions: []*PlanAction{}}
+
+			// the instances are named after their action
+			add := func(action string, count int, current string, state string) {
+				for index := 0; index < count; index++ {
+					servicePlan.Actions = append(servicePlan.Actions, &PlanAction{
+						Action:   action,
+						Version:  "1.0.0",
+						Instance: fmt.Sprintf("%s%d", action[:1], index),
+						Current:  current,
+						State:    state,
+					})
+				}
+			}
+			add(PlanActionUpdate, test.updates, model.InactiveState, model.ActiveState)
+			add(PlanActionCreate, test.creates, model.InitialState, model.ActiveState)
+			add(PlanActionRemove, test.removes, model.ActiveState, model.InitialState)
+
+			task, err := NewServiceTask(domain.Name, "", "", &servicePlan)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			main, _ := domain.GetTask(task.Subtasks[0])
+			if description := describeTasks(domain, main.GetSubtasks()); description != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, description)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
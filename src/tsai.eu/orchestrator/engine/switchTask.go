@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// NewSwitchTask creates a new task which directs the endpoint of a component
+// to a version (operation: release/promote/abort)
+func NewSwitchTask(domain string, parent string, version string, operation string) (model.Task, error) {
+	var task model.Task
+
+	// TODO: check parameters if context exists
+	task.Type = "SwitchTask"
+	task.Domain = domain
+	task.Architecture = ""
+	task.Component = ""
+	task.Version = version
+	task.Instance = ""
+	task.State = ""
+	task.Transition = operation
+	task.UUID = uuid.New().String()
+	task.Parent = parent
+	task.Status = model.TaskStatusInitial
+	task.Phase = 0
+	task.Subtasks = []string{}
+
+	// add handlers
+	BindHandlers(&task)
+
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return task, errors.New("unknown domain")
+	}
+
+	// determine parent node
+	if parent != "" {
+		parentTask, err := d.GetTask(parent)
+		if err != nil {
+			return task, errors.New("unknown parent")
+		}
+
+		// add parent context
+		task.Architecture = parentTask.Architecture
+		task.Component = parentTask.Component
+	}
+
+	// add task to domain
+	err = d.AddTask(&task)
+	if err != nil {
+		return task, err
+	}
+
+	// success
+	return task, nil
+}
+
+//------------------------------------------------------------------------------
+
+// ExecuteSwitchTask directs the endpoint of the component to the version of the task.
+func ExecuteSwitchTask(task *model.Task) {
+	// get event channel
+	channel := GetEventChannel()
+
+	// check status
+	status := task.GetStatus()
+
+	if status != model.TaskStatusInitial {
+		return
+	}
+
+	// start the execution
+	startTask(task)
+
+	// switch the endpoint
+	err := switchEndpoint(task)
+	if err != nil {
+		task.SetError(err)
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+		return
+	}
+
+	// signal completion
+	channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskCompletion, task.UUID)
+}
+
+//------------------------------------------------------------------------------
+
+// switchEndpoint applies the operation of a switch task to its component.
+func switchEndpoint(task *model.Task) error {
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		return err
+	}
+
+	component, err := domain.GetComponent(task.Component)
+	if err != nil {
+		return err
+	}
+
+	// promote or abort a release in progress
+	if task.Transition != SwitchRelease {
+		err = finishRelease(component, task.Transition)
+		if err != nil {
+			return err
+		}
+
+		journalComponent(domain.Name, component)
+		return nil
+	}
+
+	// determine the release policy of the service
+	architecture, err := domain.GetArchitecture(task.Architecture)
+	if err != nil {
+		return err
+	}
+
+	service, err := architecture.GetService(task.Component)
+	if err != nil {
+		return err
+	}
+
+	if service.Release == nil {
+		return errors.New("service has no release policy")
+	}
+
+	switchRelease(component, task.Version, service.Release)
+	journalComponent(domain.Name, component)
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,268 @@
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// taskContexts keeps track of the contexts of the operations executed by
+// tasks and of the tasks which have been cancelled before their operations
+// have started.
+var taskContexts = struct {
+	sync.Mutex
+	Cancel    map[string]context.CancelFunc // cancellation of running operations
+	Cancelled map[string]bool               // tasks which have been cancelled
+}{Cancel: map[string]context.CancelFunc{}, Cancelled: map[string]bool{}}
+
+// heldEvents keeps track of the execution events of tasks which have not been
+// started since the execution of one of their ancestors has been paused.
+var heldEvents = struct {
+	sync.Mutex
+	Events []model.Event
+}{Events: []model.Event{}}
+
+//------------------------------------------------------------------------------
+
+// taskContext provides the context for an operation executed by a task. The
+// context is cancelled if the task is cancelled. The returned function
+// releases the context once the operation has finished.
+func taskContext(task *model.Task) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	taskContexts.Lock()
+	defer taskContexts.Unlock()
+
+	// the task has been cancelled before the operation has started
+	if taskContexts.Cancelled[task.UUID] {
+		delete(taskContexts.Cancelled, task.UUID)
+		cancel()
+	}
+	taskContexts.Cancel[task.UUID] = cancel
+
+	release := func() {
+		taskContexts.Lock()
+		delete(taskContexts.Cancel, task.UUID)
+		taskContexts.Unlock()
+
+		cancel()
+	}
+
+	return ctx, release
+}
+
+//------------------------------------------------------------------------------
+
+// cancelTaskContext cancels the running operation of a task (if any).
+func cancelTaskContext(task *model.Task) {
+	taskContexts.Lock()
+	defer taskContexts.Unlock()
+
+	if cancel, found := taskContexts.Cancel[task.UUID]; found {
+		cancel()
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// CancelTask terminates a task together with all of its subtasks. Operations
+// of the subtasks which are still running are cancelled and the parent of the
+// task is informed about the failure.
+func CancelTask(domain string, uuid string) error {
+	d, task, err := controlledTask(domain, uuid)
+	if err != nil {
+		return err
+	}
+
+	// cancel the operations of the task and its subtasks
+	tasks := subtree(d, task)
+
+	taskContexts.Lock()
+	for _, subtask := range tasks {
+		if cancel, found := taskContexts.Cancel[subtask.UUID]; found {
+			cancel()
+		} else if subtask.GetStatus() <= model.TaskStatusExecuting {
+			taskContexts.Cancelled[subtask.UUID] = true
+		}
+	}
+	taskContexts.Unlock()
+
+	// discard the subtasks which have been held back
+	heldEvents.Lock()
+	remaining := []model.Event{}
+	for _, event := range heldEvents.Events {
+		if _, found := tasks[event.Task]; !found || event.Domain != domain {
+			remaining = append(remaining, event)
+		}
+	}
+	heldEvents.Events = remaining
+	heldEvents.Unlock()
+
+	task.SetError(errors.New("task has been cancelled"))
+
+	// terminate the task and inform its parent
+	channel := GetEventChannel()
+
+	channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskTermination, "cancel")
+	if task.Parent != "" {
+		channel <- model.NewEvent(task.Domain, task.Parent, model.EventTypeTaskFailure, task.UUID)
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// PauseTask prevents subtasks of a task from being started. Subtasks which are
+// already executing continue until they have finished.
+func PauseTask(domain string, uuid string) error {
+	_, task, err := controlledTask(domain, uuid)
+	if err != nil {
+		return err
+	}
+
+	if task.IsPaused() {
+		return errors.New("task has already been paused")
+	}
+
+	task.SetPaused(true)
+	journalTask(task)
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// ResumeTask continues a paused task by starting the subtasks which have been
+// held back in the meantime.
+func ResumeTask(domain string, uuid string) error {
+	d, task, err := controlledTask(domain, uuid)
+	if err != nil {
+		return err
+	}
+
+	if !task.IsPaused() {
+		return errors.New("task has not been paused")
+	}
+
+	task.SetPaused(false)
+	journalTask(task)
+
+	// determine the events which are no longer held back
+	heldEvents.Lock()
+	released := []model.Event{}
+	remaining := []model.Event{}
+	for _, event := range heldEvents.Events {
+		held, err := d.GetTask(event.Task)
+		if event.Domain == domain && err == nil && !isPaused(d, held) {
+			released = append(released, event)
+		} else {
+			remaining = append(remaining, event)
+		}
+	}
+	heldEvents.Events = remaining
+	heldEvents.Unlock()
+
+	// start the subtasks
+	channel := GetEventChannel()
+	for _, event := range released {
+		channel <- event
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// holdEvent defers the execution event of a task which must not be started
+// since the execution of one of its ancestors has been paused.
+func holdEvent(domain *model.Domain, task *model.Task, event model.Event) bool {
+	if event.Type != model.EventTypeTaskExecution || task.GetStatus() != model.TaskStatusInitial {
+		return false
+	}
+
+	if !isPaused(domain, task) {
+		return false
+	}
+
+	heldEvents.Lock()
+	heldEvents.Events = append(heldEvents.Events, event)
+	heldEvents.Unlock()
+
+	return true
+}
+
+//------------------------------------------------------------------------------
+
+// isPaused checks if a task or one of its ancestors has been paused.
+func isPaused(domain *model.Domain, task *model.Task) bool {
+	for task != nil {
+		if task.IsPaused() {
+			return true
+		}
+		if task.Parent == "" {
+			return false
+		}
+
+		parent, err := domain.GetTask(task.Parent)
+		if err != nil {
+			return false
+		}
+		task = parent
+	}
+
+	return false
+}
+
+//------------------------------------------------------------------------------
+
+// controlledTask determines a task which has not finished yet.
+func controlledTask(domain string, uuid string) (*model.Domain, *model.Task, error) {
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return nil, nil, errors.New("unknown domain")
+	}
+
+	// get task
+	task, err := d.GetTask(uuid)
+	if err != nil {
+		return nil, nil, errors.New("unknown task")
+	}
+
+	if task.GetStatus() > model.TaskStatusExecuting {
+		return nil, nil, errors.New("task has already finished")
+	}
+
+	// success
+	return d, task, nil
+}
+
+//------------------------------------------------------------------------------
+
+// subtree determines a task and all of its direct and indirect subtasks.
+func subtree(domain *model.Domain, task *model.Task) map[string]*model.Task {
+	tasks := map[string]*model.Task{task.UUID: task}
+
+	for _, uuid := range task.Subtasks {
+		subtask, err := domain.GetTask(uuid)
+		if err != nil {
+			continue
+		}
+
+		for key, value := range subtree(domain, subtask) {
+			tasks[key] = value
+		}
+	}
+
+	return tasks
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,173 @@
+package engine
+
+import (
+	"sync"
+	"testing"
+
+	ctrl "tsai.eu/orchestrator/controller"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// blockingController starts instances of the component type "blocking" only
+// once the operation has been cancelled.
+type blockingController struct {
+	testController
+}
+
+var blockingControllerOnce sync.Once
+
+//------------------------------------------------------------------------------
+
+// Start waits for the cancellation of the operation.
+func (c blockingController) Start(configuration *model.ComponentConfiguration) (*model.ComponentStatus, error) {
+	ctx := configuration.GetContext()
+	<-ctx.Done()
+
+	status, _ := c.transition("start", model.ActiveState, configuration)
+	return status, ctx.Err()
+}
+
+//------------------------------------------------------------------------------
+
+// newControlTasks creates a sequential task with approval tasks as subtasks.
+func newControlTasks(t *testing.T, domain *model.Domain, approvals int) (*model.Task, []*model.Task) {
+	created, err := NewSequentialTask(domain.Name, "", []string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task, _ := domain.GetTask(created.UUID)
+
+	subtasks := []*model.Task{}
+	for index := 0; index < approvals; index++ {
+		approval, _ := NewApprovalTask(domain.Name, task.UUID, "gate", 0)
+		subtask, _ := domain.GetTask(approval.UUID)
+		task.AddSubtask(subtask)
+		subtasks = append(subtasks, subtask)
+	}
+
+	return task, subtasks
+}
+
+//------------------------------------------------------------------------------
+
+func TestPauseTask(t *testing.T) {
+	domain := newTestDomain(t, map[string][]string{})
+	defer model.GetModel().DeleteDomain(domain.Name)
+
+	dispatcher := startTestDispatcher()
+	defer stopTestDispatcher(t, dispatcher)
+
+	task, approvals := newControlTasks(t, domain, 2)
+
+	dispatcher.Channel <- model.NewEvent(domain.Name, task.UUID, model.EventTypeTaskExecution, "")
+	waitFor(t, "first approval", func() bool { return approvals[0].GetStatus() == model.TaskStatusExecuting })
+
+	if err := PauseTask(domain.Name, task.UUID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := PauseTask(domain.Name, task.UUID); err == nil {
+		t.Errorf("expected an error for a paused task")
+	}
+
+	// the next subtask is held back while the task is paused
+	ApproveTask(domain.Name, approvals[0].UUID)
+	waitFor(t, "completion of the first approval", func() bool { return approvals[0].GetStatus() == model.TaskStatusCompleted })
+	waitIdle(t, dispatcher)
+
+	if status := approvals[1].GetStatus(); status != model.TaskStatusInitial {
+		t.Errorf("expected the second approval to be held back, got status %v", status)
+	}
+
+	// the held back subtask is started once the task is resumed
+	if err := ResumeTask(domain.Name, task.UUID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitFor(t, "second approval", func() bool { return approvals[1].GetStatus() == model.TaskStatusExecuting })
+
+	ApproveTask(domain.Name, approvals[1].UUID)
+	waitFor(t, "task to finish", func() bool { return task.GetStatus() > model.TaskStatusExecuting })
+	waitIdle(t, dispatcher)
+
+	if status := task.GetStatus(); status != model.TaskStatusCompleted {
+		t.Errorf("expected status %v, got %v", model.TaskStatusCompleted, status)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestCancelTask(t *testing.T) {
+	blockingControllerOnce.Do(func() {
+		if err := ctrl.RegisterController("blocking", blockingController{}, nil); err != nil {
+			t.Fatalf("unable to register controller: %v", err)
+		}
+	})
+
+	domain := newTestDomain(t, map[string][]string{"app": {}})
+	defer model.GetModel().DeleteDomain(domain.Name)
+
+	template, _ := domain.GetTemplate("app")
+	template.Type = "blocking"
+	component, _ := model.NewComponent("app", "blocking")
+	domain.AddComponent(component)
+	instance := addTestInstance(t, domain, "app", model.InactiveState, "")
+
+	dispatcher := startTestDispatcher()
+	defer stopTestDispatcher(t, dispatcher)
+
+	// a transition whose operation is running
+	parent := newTestTask(t, domain, "")
+	parent.Component = "app"
+	parent.Version = "1.0.0"
+	parent.Instance = instance.UUID
+
+	created, err := NewTransitionTask(domain.Name, parent.UUID, "start", model.ActiveState)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transition, _ := domain.GetTask(created.UUID)
+	parent.AddSubtask(transition)
+
+	// an approval which is pending
+	task, approvals := newControlTasks(t, domain, 1)
+
+	for _, uuid := range []string{parent.UUID, task.UUID} {
+		dispatcher.Channel <- model.NewEvent(domain.Name, uuid, model.EventTypeTaskExecution, "")
+	}
+	waitFor(t, "running operation", func() bool {
+		taskContexts.Lock()
+		defer taskContexts.Unlock()
+		return taskContexts.Cancel[transition.UUID] != nil
+	})
+	waitFor(t, "pending approval", func() bool { return approvals[0].GetStatus() == model.TaskStatusExecuting })
+
+	for _, uuid := range []string{parent.UUID, task.UUID} {
+		if err := CancelTask(domain.Name, uuid); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	waitFor(t, "termination", func() bool {
+		return parent.GetStatus() > model.TaskStatusExecuting && task.GetStatus() > model.TaskStatusExecuting
+	})
+	waitIdle(t, dispatcher)
+
+	for _, cancelled := range []*model.Task{parent, transition, task, approvals[0]} {
+		if status := cancelled.GetStatus(); status != model.TaskStatusTerminated {
+			t.Errorf("expected status %v of %s, got %v", model.TaskStatusTerminated, cancelled.Type, status)
+		}
+	}
+	if task.GetError() != "task has been cancelled" {
+		t.Errorf("unexpected error '%s'", task.GetError())
+	}
+
+	// the result of the cancelled operation is discarded
+	if instance.State != model.InactiveState {
+		t.Errorf("expected state %s, got %s", model.InactiveState, instance.State)
+	}
+	if err := CancelTask(domain.Name, task.UUID); err == nil {
+		t.Errorf("expected an error for a finished task")
+	}
+}
+
+//------------------------------------------------------------------------------
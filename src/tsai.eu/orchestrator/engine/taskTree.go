@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// TaskTree renders the hierarchy of a task and its subtasks as text.
+func TaskTree(domain string, uuid string) (string, error) {
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return "", errors.New("unknown domain")
+	}
+
+	// get task
+	task, err := d.GetTask(uuid)
+	if err != nil {
+		return "", errors.New("unknown task")
+	}
+
+	var builder strings.Builder
+
+	renderTask(&builder, d, task, "", "", "", time.Now().UnixNano())
+
+	// success
+	return builder.String(), nil
+}
+
+//------------------------------------------------------------------------------
+
+// renderTask writes a line describing a task followed by the lines of its
+// subtasks indented by their depth in the hierarchy.
+func renderTask(builder *strings.Builder, domain *model.Domain, task *model.Task, architecture string, prefix string, indent string, now int64) {
+	// status and duration
+	status, _ := model.TaskStatus2String(task.GetStatus())
+	if task.IsPaused() {
+		status = status + ", paused"
+	}
+
+	duration := "-"
+	if started := task.GetStarted(); started != 0 {
+		end := task.GetFinished()
+		if end == 0 {
+			end = now
+		}
+		duration = time.Duration(end - started).Round(time.Millisecond).String()
+	}
+
+	line := fmt.Sprintf("%s%s [%s] %s", prefix, task.Type, status, duration)
+
+	// entity addressed by the task (the architecture is only shown if it
+	// differs from the one of the parent task)
+	entity := []string{}
+	if task.Architecture != "" && task.Architecture != architecture {
+		entity = append(entity, "architecture="+task.Architecture)
+	}
+	if task.Component != "" {
+		entity = append(entity, "component="+task.Component)
+	}
+	if task.Version != "" {
+		entity = append(entity, "version="+task.Version)
+	}
+	if task.Instance != "" {
+		entity = append(entity, "instance="+task.Instance)
+	}
+	if task.State != "" {
+		entity = append(entity, "state="+task.State)
+	}
+	if task.Transition != "" {
+		entity = append(entity, "transition="+task.Transition)
+	}
+	if task.Hook != "" {
+		entity = append(entity, "hook="+task.Hook)
+	}
+	if task.Gate != "" {
+		entity = append(entity, "gate="+task.Gate)
+	}
+	if len(entity) > 0 {
+		line = line + " " + strings.Join(entity, " ")
+	}
+
+	line = line + " (" + task.UUID + ")"
+	if message := task.GetError(); message != "" {
+		line = line + " error: " + message
+	}
+
+	builder.WriteString(line + "\n")
+
+	// subtasks
+	for index, uuid := range task.Subtasks {
+		branch, next := "├─ ", "│  "
+		if index == len(task.Subtasks)-1 {
+			branch, next = "└─ ", "   "
+		}
+
+		subtask, err := domain.GetTask(uuid)
+		if err != nil {
+			builder.WriteString(indent + branch + "unknown task (" + uuid + ")\n")
+			continue
+		}
+
+		renderTask(builder, domain, subtask, task.Architecture, indent+branch, indent+next, now)
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// TaskType describes the behaviour of all tasks of a type. Handlers which are
+// not defined default to the generic handlers of the engine.
+type TaskType struct {
+	Name      string            // name of the type (corresponds to Task.Type)
+	Execute   model.TaskHandler // handles execution events
+	Terminate model.TaskHandler // handles termination events
+	Failed    model.TaskHandler // handles failure events
+	Timeout   model.TaskHandler // handles timeout events
+	Completed model.TaskHandler // handles completion events
+}
+
+// taskTypes is the registry of all known task types.
+var taskTypes = struct {
+	sync.RWMutex
+	Map map[string]*TaskType
+}{Map: map[string]*TaskType{}}
+
+//------------------------------------------------------------------------------
+
+// register the task types of the engine
+func init() {
+	RegisterTaskType(&TaskType{Name: "ArchitectureTask", Execute: ExecuteSequentialTask})
+	RegisterTaskType(&TaskType{Name: "SequentialTask", Execute: ExecuteSequentialTask})
+	RegisterTaskType(&TaskType{Name: "ParallelTask", Execute: ExecuteParallelTask})
+	RegisterTaskType(&TaskType{Name: "ServiceTask", Execute: ExecuteServiceTask})
+	RegisterTaskType(&TaskType{Name: "InstanceTask", Execute: ExecuteInstanceTask})
+	RegisterTaskType(&TaskType{Name: "TransitionTask", Execute: ExecuteTransitionTask})
+	RegisterTaskType(&TaskType{Name: "WaitTask", Execute: ExecuteWaitTask})
+	RegisterTaskType(&TaskType{Name: "SwitchTask", Execute: ExecuteSwitchTask})
+	RegisterTaskType(&TaskType{Name: "ApprovalTask", Execute: ExecuteApprovalTask})
+	RegisterTaskType(&TaskType{Name: "HookTask", Execute: ExecuteHookTask})
+	RegisterTaskType(&TaskType{Name: "ReadinessTask", Execute: ExecuteReadinessTask})
+	RegisterTaskType(&TaskType{Name: "CascadeTask", Execute: ExecuteSequentialTask})
+}
+
+//------------------------------------------------------------------------------
+
+// RegisterTaskType adds a task type to the registry.
+func RegisterTaskType(taskType *TaskType) error {
+	if taskType.Name == "" {
+		return errors.New("task type requires a name")
+	}
+
+	if taskType.Execute == nil {
+		return errors.Errorf("task type '%s' requires an execute handler", taskType.Name)
+	}
+
+	// default to the generic handlers
+	result := *taskType
+	if result.Terminate == nil {
+		result.Terminate = TerminateTask
+	}
+	if result.Failed == nil {
+		result.Failed = FailedTask
+	}
+	if result.Timeout == nil {
+		result.Timeout = TimeoutTask
+	}
+	if result.Completed == nil {
+		result.Completed = CompletedTask
+	}
+
+	taskTypes.Lock()
+	defer taskTypes.Unlock()
+
+	if _, found := taskTypes.Map[result.Name]; found {
+		return errors.Errorf("task type '%s' already exists", result.Name)
+	}
+
+	taskTypes.Map[result.Name] = &result
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// GetTaskType provides the task type with a given name.
+func GetTaskType(name string) (*TaskType, error) {
+	taskTypes.RLock()
+	defer taskTypes.RUnlock()
+
+	taskType, found := taskTypes.Map[name]
+	if !found {
+		return nil, errors.Errorf("unknown task type: '%s'", name)
+	}
+
+	// success
+	return taskType, nil
+}
+
+//------------------------------------------------------------------------------
+
+// ListTaskTypes provides the names of all registered task types.
+func ListTaskTypes() []string {
+	taskTypes.RLock()
+	defer taskTypes.RUnlock()
+
+	names := []string{}
+	for name := range taskTypes.Map {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+//------------------------------------------------------------------------------
+
+// BindHandlers defines the event handlers of a task according to its type.
+func BindHandlers(task *model.Task) error {
+	taskType, err := GetTaskType(task.Type)
+	if err != nil {
+		return err
+	}
+
+	task.SetExecute(taskType.Execute)
+	task.SetTerminate(taskType.Terminate)
+	task.SetFailed(taskType.Failed)
+	task.SetTimeout(taskType.Timeout)
+	task.SetCompleted(taskType.Completed)
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// NewTask creates a new task of a registered type which inherits the context
+// of its parent. It allows task types defined outside of the engine to be
+// added to a task hierarchy.
+func NewTask(domain string, parent string, taskType string) (model.Task, error) {
+	var task model.Task
+
+	task.Type = taskType
+	task.Domain = domain
+	task.UUID = uuid.New().String()
+	task.Parent = parent
+	task.Status = model.TaskStatusInitial
+	task.Phase = 0
+	task.Subtasks = []string{}
+
+	// add handlers
+	err := BindHandlers(&task)
+	if err != nil {
+		return task, err
+	}
+
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return task, errors.New("unknown domain")
+	}
+
+	// determine parent node
+	if parent != "" {
+		parentTask, err := d.GetTask(parent)
+		if err != nil {
+			return task, errors.New("unknown parent")
+		}
+
+		// add parent context
+		task.Architecture = parentTask.Architecture
+		task.Component = parentTask.Component
+		task.Version = parentTask.Version
+		task.Instance = parentTask.Instance
+	}
+
+	// add task to domain
+	err = d.AddTask(&task)
+	if err != nil {
+		return task, err
+	}
+
+	// success
+	return task, nil
+}
+
+//------------------------------------------------------------------------------
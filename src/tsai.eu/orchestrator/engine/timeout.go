@@ -0,0 +1,281 @@
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// DefaultTimeouts defines the default timeouts in seconds per task type (0 = none).
+var DefaultTimeouts = map[string]int{
+	"ArchitectureTask": 3600,
+	"CascadeTask":      3600,
+	"ServiceTask":      1800,
+	"InstanceTask":     900,
+	"TransitionTask":   300,
+	"HookTask":         300,
+	"ReadinessTask":    600,
+	"ParallelTask":     0,
+	"SequentialTask":   0,
+	"ApprovalTask":     0,
+}
+
+//------------------------------------------------------------------------------
+
+// determineTimeout determines the timeout in seconds of a task.
+func determineTimeout(task *model.Task) int {
+	// check for overrides defined by the template variant of an instance
+	switch task.Type {
+	case "TransitionTask":
+		if timeout, found := variantTimeout(task, task.Transition); found {
+			return timeout
+		}
+	case "HookTask":
+		if timeout, found := hookTimeout(task); found {
+			return timeout
+		}
+	case "ReadinessTask":
+		if timeout, found := readinessTimeout(task); found {
+			return timeout
+		}
+	}
+
+	// use the default of the task type
+	return DefaultTimeouts[task.Type]
+}
+
+//------------------------------------------------------------------------------
+
+// variantTimeout determines the timeout of a transition defined by the template
+// variant which corresponds to the component and version of a task.
+func variantTimeout(task *model.Task, transition string) (int, bool) {
+	variant, err := taskVariant(task)
+	if err != nil {
+		return 0, false
+	}
+
+	timeout, err := variant.GetTimeout(transition)
+	if err != nil {
+		return 0, false
+	}
+
+	// success
+	return timeout, true
+}
+
+//------------------------------------------------------------------------------
+
+// hookTimeout determines the timeout of the hook executed by a task.
+func hookTimeout(task *model.Task) (int, bool) {
+	variant, err := taskVariant(task)
+	if err != nil {
+		return 0, false
+	}
+
+	hook, err := variant.GetHook(task.Hook)
+	if err != nil || hook.Timeout <= 0 {
+		return 0, false
+	}
+
+	// success
+	return hook.Timeout, true
+}
+
+//------------------------------------------------------------------------------
+
+// readinessTimeout determines the timeout of a readiness task from the
+// deadline of the readiness probe (allowing the last check to finish).
+func readinessTimeout(task *model.Task) (int, bool) {
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		return 0, false
+	}
+
+	component, err := domain.GetComponent(task.Component)
+	if err != nil {
+		return 0, false
+	}
+
+	probe := determineProbe(domain, component, task.Version, ProbeReadiness)
+	if probe == nil || probe.Deadline <= 0 {
+		return 0, false
+	}
+
+	// success
+	return probe.Deadline + probe.Timeout, true
+}
+
+//------------------------------------------------------------------------------
+
+// taskVariant determines the template variant which corresponds to the
+// component and version of a task.
+func taskVariant(task *model.Task) (*model.Variant, error) {
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := domain.GetTemplate(task.Component)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.GetVariant(task.Version)
+}
+
+//------------------------------------------------------------------------------
+
+// deadlines keeps track of the executing tasks which have a deadline and of
+// the tasks whose deadlines are suspended while they wait for an approval.
+var deadlines = struct {
+	sync.Mutex
+	Tasks     map[string]*model.Task // executing tasks with a deadline
+	Suspended map[string]int         // number of pending approvals per task
+	Approvals map[string]bool        // pending approvals suspending deadlines
+}{Tasks: map[string]*model.Task{}, Suspended: map[string]int{}, Approvals: map[string]bool{}}
+
+//------------------------------------------------------------------------------
+
+// startTask marks a task as executing, records the start of its execution and
+// arms its deadline.
+func startTask(task *model.Task) {
+	now := time.Now().UnixNano()
+
+	task.SetStarted(now)
+	task.SetStatus(model.TaskStatusExecuting)
+
+	// define deadline if a timeout applies
+	if timeout := determineTimeout(task); timeout > 0 && task.GetDeadline() == 0 {
+		task.SetDeadline(now + int64(timeout)*int64(time.Second))
+	}
+
+	trackDeadline(task)
+}
+
+//------------------------------------------------------------------------------
+
+// trackDeadline registers an executing task with a deadline for the periodic
+// deadline checks of the dispatcher.
+func trackDeadline(task *model.Task) {
+	if task.GetDeadline() == 0 {
+		return
+	}
+
+	deadlines.Lock()
+	deadlines.Tasks[task.Domain+"/"+task.UUID] = task
+	deadlines.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
+// expiredTasks determines the tracked tasks which have exceeded their deadline.
+// Tasks which have expired or finished are no longer tracked.
+func expiredTasks(now int64) []*model.Task {
+	deadlines.Lock()
+	defer deadlines.Unlock()
+
+	expired := []*model.Task{}
+	for key, task := range deadlines.Tasks {
+		switch {
+		case task.GetStatus() != model.TaskStatusExecuting:
+			delete(deadlines.Tasks, key)
+		case isExpired(task, now):
+			delete(deadlines.Tasks, key)
+			expired = append(expired, task)
+		}
+	}
+
+	return expired
+}
+
+//------------------------------------------------------------------------------
+
+// isExpired determines if the deadline of an executing task has passed. The
+// deadline does not pass while the task waits for an approval.
+func isExpired(task *model.Task, now int64) bool {
+	if deadlines.Suspended[task.Domain+"/"+task.UUID] > 0 {
+		return false
+	}
+
+	deadline := task.GetDeadline()
+
+	return task.GetStatus() == model.TaskStatusExecuting && deadline > 0 && now > deadline
+}
+
+//------------------------------------------------------------------------------
+
+// suspendDeadlines stops the deadlines of the ancestors of an approval task
+// while the approval is pending.
+func suspendDeadlines(approval *model.Task) {
+	deadlines.Lock()
+	defer deadlines.Unlock()
+
+	key := approval.Domain + "/" + approval.UUID
+	if deadlines.Approvals[key] {
+		return
+	}
+	deadlines.Approvals[key] = true
+
+	for _, ancestor := range ancestors(approval) {
+		deadlines.Suspended[ancestor.Domain+"/"+ancestor.UUID]++
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// resumeDeadlines restarts the deadlines of the ancestors of an approval task
+// which has finished. The deadlines are extended by the time the approval has
+// been pending.
+func resumeDeadlines(approval *model.Task) {
+	deadlines.Lock()
+	defer deadlines.Unlock()
+
+	key := approval.Domain + "/" + approval.UUID
+	if !deadlines.Approvals[key] {
+		return
+	}
+	delete(deadlines.Approvals, key)
+
+	pending := time.Now().UnixNano() - approval.GetStarted()
+
+	for _, ancestor := range ancestors(approval) {
+		akey := ancestor.Domain + "/" + ancestor.UUID
+
+		deadlines.Suspended[akey]--
+		if deadlines.Suspended[akey] <= 0 {
+			delete(deadlines.Suspended, akey)
+		}
+
+		if deadline := ancestor.GetDeadline(); deadline > 0 {
+			ancestor.SetDeadline(deadline + pending)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// ancestors determines the parent of a task, the parent of the parent etc.
+func ancestors(task *model.Task) []*model.Task {
+	result := []*model.Task{}
+
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		return result
+	}
+
+	for task.Parent != "" {
+		parent, err := domain.GetTask(task.Parent)
+		if err != nil {
+			break
+		}
+		result = append(result, parent)
+		task = parent
+	}
+
+	return result
+}
+
+//------------------------------------------------------------------------------
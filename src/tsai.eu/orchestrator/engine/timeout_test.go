@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// newTestTask creates a parallel task which is registered within a domain.
+func newTestTask(t *testing.T, domain *model.Domain, parent string) *model.Task {
+	task, err := NewParallelTask(domain.Name, parent, []string{})
+	if err != nil {
+		t.Fatalf("unable to create task: %v", err)
+	}
+
+	stored, _ := domain.GetTask(task.UUID)
+	return stored
+}
+
+//------------------------------------------------------------------------------
+
+func TestDeadlines(t *testing.T) {
+	domain := newTestDomain(t, map[string][]string{})
+	defer model.GetModel().DeleteDomain(domain.Name)
+
+	DefaultTimeouts["ParallelTask"] = 60
+	defer func() { DefaultTimeouts["ParallelTask"] = 0 }()
+
+	parent := newTestTask(t, domain, "")
+	task := newTestTask(t, domain, parent.UUID)
+	finished := newTestTask(t, domain, parent.UUID)
+
+	later := time.Now().Add(time.Hour).UnixNano()
+
+	// initial tasks have no deadline
+	if expired := expiredTasks(later); len(expired) != 0 {
+		t.Fatalf("unexpected expired tasks %v", expired)
+	}
+
+	// the deadline is armed when the task starts
+	startTask(task)
+	startTask(finished)
+	if deadline := task.GetDeadline(); deadline <= time.Now().UnixNano() || deadline > later {
+		t.Fatalf("unexpected deadline %d", deadline)
+	}
+	if expired := expiredTasks(time.Now().UnixNano()); len(expired) != 0 {
+		t.Fatalf("unexpected expired tasks %v", expired)
+	}
+
+	// finished tasks are no longer tracked
+	finished.SetStatus(model.TaskStatusCompleted)
+	finished.SetDeadline(1)
+
+	// the dispatcher times out the expired task which signals its parent
+	task.SetDeadline(time.Now().UnixNano() - 1)
+
+	dispatcher := Dispatcher{Model: model.GetModel(), Pool: NewWorkerPool(1, 0, 0)}
+	go dispatcher.checkDeadlines()
+
+	select {
+	case event := <-GetEventChannel():
+		if event.Task != parent.UUID || event.Type != model.EventTypeTaskTimeout || event.Source != task.UUID {
+			t.Errorf("unexpected event %v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("task has not timed out")
+	}
+
+	if status := task.GetStatus(); status != model.TaskStatusTimeout {
+		t.Errorf("expected status %v, got %v", model.TaskStatusTimeout, status)
+	}
+	if expired := expiredTasks(later); len(expired) != 0 {
+		t.Errorf("unexpected expired tasks %v", expired)
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,199 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	ctrl "tsai.eu/orchestrator/controller"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// NewTransitionTask creates a new task which executes a single transition of an instance
+func NewTransitionTask(domain string, parent string, transition string, state string) (model.Task, error) {
+	var task model.Task
+
+	// TODO: check parameters if context exists
+	task.Type = "TransitionTask"
+	task.Domain = domain
+	task.Architecture = ""
+	task.Component = ""
+	task.Version = ""
+	task.Instance = ""
+	task.State = state
+	task.Transition = transition
+	task.UUID = uuid.New().String()
+	task.Parent = parent
+	task.Status = model.TaskStatusInitial
+	task.Phase = 0
+	task.Subtasks = []string{}
+
+	// add handlers
+	BindHandlers(&task)
+
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return task, errors.New("unknown domain")
+	}
+
+	// determine parent node
+	parentTask, err := d.GetTask(parent)
+	if err != nil {
+		return task, errors.New("unknown parent")
+	}
+
+	// add parent context
+	task.Architecture = parentTask.Architecture
+	task.Component = parentTask.Component
+	task.Version = parentTask.Version
+	task.Instance = parentTask.Instance
+
+	// add task to domain
+	err = d.AddTask(&task)
+	if err != nil {
+		return task, err
+	}
+
+	// success
+	return task, nil
+}
+
+//------------------------------------------------------------------------------
+
+// ExecuteTransitionTask is the main task execution routine.
+func ExecuteTransitionTask(task *model.Task) {
+	// get event channel
+	channel := GetEventChannel()
+
+	// check status
+	status := task.GetStatus()
+
+	if status != model.TaskStatusInitial && status != model.TaskStatusExecuting {
+		return
+	}
+
+	// initialize if needed
+	if status == model.TaskStatusInitial {
+		// start the execution
+		startTask(task)
+	}
+
+	// execute the transition
+	ctx, release := taskContext(task)
+	err := executeTransition(ctx, task)
+	cancelled := ctx.Err() != nil
+	release()
+
+	// a cancelled task is concluded by its termination or timeout
+	if cancelled {
+		return
+	}
+
+	// check for errors and retry if permitted
+	if err != nil {
+		if retryTask(task, err) {
+			return
+		}
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskFailure, task.UUID)
+	} else {
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskCompletion, task.UUID)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// executeTransition verifies the current state of an instance and triggers the
+// controller to execute the transition.
+func executeTransition(ctx context.Context, task *model.Task) error {
+	// collect relevant information
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		return err
+	}
+	err = provideComponent(domain, task.Component)
+	if err != nil {
+		return err
+	}
+	component, err := domain.GetComponent(task.Component)
+	if err != nil {
+		return err
+	}
+	instance, err := provideInstance(domain, component, task.Instance, task.Version)
+	if err != nil {
+		return err
+	}
+	controller, err := ctrl.GetController(component.Type)
+	if err != nil {
+		return err
+	}
+	configuration, err := model.GetConfiguration(domain.Name, component.Name, instance.UUID)
+	if err != nil {
+		return err
+	}
+	configuration.Context = ctx
+
+	// re-check the current state of the instance
+	currentStatus, err := controller.Status(configuration)
+	if currentStatus == nil {
+		if err == nil {
+			err = errors.New("status of instance not reported by controller")
+		}
+		return err
+	}
+
+	// verify that the transition is possible in the current state and skip
+	// the transition if the resulting state has already been reached
+	machine := model.GetStateMachine(component.Type)
+	transition, err := machine.GetTransition(currentStatus.InstanceState, task.Transition)
+	if err != nil {
+		if currentStatus.InstanceState == task.State {
+			applyStatus(instance, currentStatus)
+			journalInstance(domain.Name, component, instance)
+			return nil
+		}
+		return fmt.Errorf("transition '%s' not possible in state '%s'", task.Transition, currentStatus.InstanceState)
+	}
+
+	// record attempt
+	task.Attempts++
+
+	// update the dependency endpoints of the instance
+	instance.SetDependencies(model.DetermineDependencies(domain, component, instance))
+
+	// execute the controller method implementing the transition
+	result, err := ctrl.Execute(controller, transition.Method, configuration)
+	if err != nil {
+		return err
+	}
+
+	// record the new status
+	applyStatus(instance, result)
+	journalInstance(domain.Name, component, instance)
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// applyStatus records the status reported by a controller in the model.
+func applyStatus(instance *model.Instance, status *model.ComponentStatus) {
+	if status == nil {
+		return
+	}
+
+	// update component and instance information
+	if status.Changed {
+		model.SetStatus(*status)
+		return
+	}
+
+	// update the state of the instance only
+	model.SetInstanceState(instance, status.InstanceState)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// NewWaitTask creates a new task which pauses for a delay in milliseconds
+func NewWaitTask(domain string, parent string, delay int) (model.Task, error) {
+	var task model.Task
+
+	// TODO: check parameters if context exists
+	task.Type = "WaitTask"
+	task.Domain = domain
+	task.Architecture = ""
+	task.Component = ""
+	task.Version = ""
+	task.Instance = ""
+	task.State = ""
+	task.UUID = uuid.New().String()
+	task.Parent = parent
+	task.Status = model.TaskStatusInitial
+	task.Phase = 0
+	task.Subtasks = []string{}
+	task.Delay = delay
+
+	// add handlers
+	BindHandlers(&task)
+
+	// get domain
+	d, err := model.GetModel().GetDomain(domain)
+	if err != nil {
+		return task, errors.New("unknown domain")
+	}
+
+	// determine parent node
+	if parent != "" {
+		parentTask, err := d.GetTask(parent)
+		if err != nil {
+			return task, errors.New("unknown parent")
+		}
+
+		// add parent context
+		task.Architecture = parentTask.Architecture
+		task.Component = parentTask.Component
+	}
+
+	// add task to domain
+	err = d.AddTask(&task)
+	if err != nil {
+		return task, err
+	}
+
+	// success
+	return task, nil
+}
+
+//------------------------------------------------------------------------------
+
+// ExecuteWaitTask completes the task once its delay has passed.
+func ExecuteWaitTask(task *model.Task) {
+	// get event channel
+	channel := GetEventChannel()
+
+	// check status
+	status := task.GetStatus()
+
+	if status != model.TaskStatusInitial && status != model.TaskStatusExecuting {
+		return
+	}
+
+	// initialize if needed
+	if status == model.TaskStatusInitial {
+		// start the execution
+		startTask(task)
+	}
+
+	// determine the remaining delay (the task may have been resumed)
+	started := task.GetStarted()
+	if started == 0 {
+		started = time.Now().UnixNano()
+	}
+	remaining := time.Until(time.Unix(0, started).Add(time.Duration(task.Delay) * time.Millisecond))
+
+	// signal completion after the delay
+	time.AfterFunc(remaining, func() {
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskCompletion, task.UUID)
+	})
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,306 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// Kinds of stalls detected by the watchdog.
+const (
+	// StallFinished resembles an executing task whose subtasks have all finished.
+	StallFinished = "finished"
+	// StallInactive resembles a task tree which has not handled any events for a while.
+	StallInactive = "inactive"
+)
+
+// stallGrace defines how long a task may take to notice that all of its
+// subtasks have finished.
+const stallGrace = 10 * time.Second
+
+//------------------------------------------------------------------------------
+
+// Stall describes a task which has stopped making progress.
+type Stall struct {
+	Task   *model.Task // task which has stalled
+	Kind   string      // kind of stall: finished/inactive
+	Detail string      // description of the situation
+}
+
+// taskActivity keeps track of the time of the last event handled per task.
+var taskActivity = struct {
+	sync.Mutex
+	Map map[string]int64
+}{Map: map[string]int64{}}
+
+// reportedStalls keeps track of the stalls which have already been reported.
+var reportedStalls = map[string]bool{}
+var reportedStallsLock sync.Mutex
+
+//------------------------------------------------------------------------------
+
+// recordActivity records the time of an event handled by the dispatcher.
+func recordActivity(event *model.Event) {
+	taskActivity.Lock()
+	defer taskActivity.Unlock()
+
+	taskActivity.Map[event.Task] = event.Time
+}
+
+//------------------------------------------------------------------------------
+
+// lastActivity determines the time of the last event handled by a task.
+func lastActivity(task *model.Task) int64 {
+	taskActivity.Lock()
+	last := taskActivity.Map[task.UUID]
+	taskActivity.Unlock()
+
+	if started := task.GetStarted(); started > last {
+		last = started
+	}
+	if finished := task.GetFinished(); finished > last {
+		last = finished
+	}
+	return last
+}
+
+//------------------------------------------------------------------------------
+
+// StartWatchdog periodically checks the task trees of all domains of the
+// model for stalls and optionally repairs them.
+func StartWatchdog(m *model.Model, interval time.Duration, period time.Duration, repair bool) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			domains, _ := m.ListDomains()
+			for _, name := range domains {
+				domain, err := m.GetDomain(name)
+				if err != nil {
+					continue
+				}
+
+				CheckStalls(domain, period, repair)
+			}
+		}
+	}()
+}
+
+//------------------------------------------------------------------------------
+
+// CheckStalls determines the executing tasks of a domain which have stopped
+// making progress: tasks whose subtasks have all finished but which are still
+// executing and task trees which have not handled any events for the given
+// period. Newly detected stalls are recorded as events. If requested, the
+// missing execution events are re-emitted.
+func CheckStalls(domain *model.Domain, period time.Duration, repair bool) []*Stall {
+	now := time.Now().UnixNano()
+	stalls := []*Stall{}
+
+	uuids, _ := domain.ListTasks()
+	sort.Strings(uuids)
+	for _, uuid := range uuids {
+		root, err := domain.GetTask(uuid)
+		if err != nil || root.Parent != "" || root.GetStatus() != model.TaskStatusExecuting {
+			continue
+		}
+
+		tree := subtree(domain, root)
+
+		// trees which are paused or wait for an approval are not stalled
+		if isWaiting(tree) {
+			continue
+		}
+
+		// determine executing tasks whose subtasks have all finished
+		keys := []string{}
+		for key := range tree {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		last := int64(0)
+		found := false
+		for _, key := range keys {
+			task := tree[key]
+
+			if activity := lastActivity(task); activity > last {
+				last = activity
+			}
+
+			finished, ok := subtasksFinished(domain, task)
+			if !ok || task.GetStatus() != model.TaskStatusExecuting || now-finished < int64(stallGrace) {
+				continue
+			}
+
+			found = true
+			stalls = append(stalls, &Stall{
+				Task:   task,
+				Kind:   StallFinished,
+				Detail: fmt.Sprintf("all subtasks of %s '%s' have finished %s ago but it is still executing", task.Type, task.UUID, elapsed(now, finished)),
+			})
+
+			if repair {
+				GetEventChannel() <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskExecution, "watchdog")
+			}
+		}
+
+		// determine trees without any activity
+		if found || now-last < int64(period) {
+			continue
+		}
+
+		stalls = append(stalls, &Stall{
+			Task:   root,
+			Kind:   StallInactive,
+			Detail: fmt.Sprintf("%s '%s' has not handled any events for %s", root.Type, root.UUID, elapsed(now, last)),
+		})
+
+		if repair {
+			restartFrontier(domain, tree)
+		}
+	}
+
+	recordStalls(domain, stalls)
+
+	return stalls
+}
+
+//------------------------------------------------------------------------------
+
+// isWaiting checks if a task tree waits for a manual intervention.
+func isWaiting(tree map[string]*model.Task) bool {
+	for _, task := range tree {
+		if task.IsPaused() {
+			return true
+		}
+		if task.Type == "ApprovalTask" && task.GetStatus() == model.TaskStatusExecuting {
+			return true
+		}
+	}
+	return false
+}
+
+//------------------------------------------------------------------------------
+
+// subtasksFinished determines if a task has subtasks which have all finished
+// and when the last of them has finished.
+func subtasksFinished(domain *model.Domain, task *model.Task) (int64, bool) {
+	if len(task.Subtasks) == 0 {
+		return 0, false
+	}
+
+	finished := int64(0)
+	for _, uuid := range task.Subtasks {
+		subtask, err := domain.GetTask(uuid)
+		if err != nil || subtask.GetStatus() <= model.TaskStatusExecuting {
+			return 0, false
+		}
+
+		if activity := lastActivity(subtask); activity > finished {
+			finished = activity
+		}
+	}
+
+	if activity := lastActivity(task); activity > finished {
+		finished = activity
+	}
+
+	return finished, true
+}
+
+//------------------------------------------------------------------------------
+
+// restartFrontier re-emits the execution events of the executing tasks of a
+// tree which do not have any executing subtasks. Parallel tasks start their
+// subtasks which have not been started yet, all other tasks re-evaluate the
+// status of their subtasks. Tasks without subtasks are not re-executed since
+// their operations may still be running.
+func restartFrontier(domain *model.Domain, tree map[string]*model.Task) {
+	channel := GetEventChannel()
+
+	for _, task := range tree {
+		if task.GetStatus() != model.TaskStatusExecuting || len(task.Subtasks) == 0 {
+			continue
+		}
+
+		initial := []string{}
+		executing := false
+		for _, uuid := range task.Subtasks {
+			subtask, err := domain.GetTask(uuid)
+			if err != nil {
+				continue
+			}
+
+			switch subtask.GetStatus() {
+			case model.TaskStatusInitial:
+				initial = append(initial, uuid)
+			case model.TaskStatusExecuting:
+				executing = true
+			}
+		}
+
+		if executing {
+			continue
+		}
+
+		if task.Type == "ParallelTask" {
+			for _, uuid := range initial {
+				channel <- model.NewEvent(task.Domain, uuid, model.EventTypeTaskExecution, "watchdog")
+			}
+			continue
+		}
+
+		channel <- model.NewEvent(task.Domain, task.UUID, model.EventTypeTaskExecution, "watchdog")
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// recordStalls records events for stalls which have not been reported before
+// and forgets about stalls which have disappeared.
+func recordStalls(domain *model.Domain, stalls []*Stall) {
+	reportedStallsLock.Lock()
+	defer reportedStallsLock.Unlock()
+
+	prefix := domain.Name + "/"
+
+	current := map[string]bool{}
+	for _, stall := range stalls {
+		key := prefix + stall.Task.UUID + "/" + stall.Kind
+		current[key] = true
+
+		if reportedStalls[key] {
+			continue
+		}
+		reportedStalls[key] = true
+
+		event := model.NewEvent(domain.Name, stall.Task.UUID, model.EventTypeStall, "watchdog")
+		event.Detail = stall.Detail
+
+		recordEvent(domain, &event)
+	}
+
+	// forget about stalls which have disappeared
+	for key := range reportedStalls {
+		if strings.HasPrefix(key, prefix) && !current[key] {
+			delete(reportedStalls, key)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// elapsed describes the time which has passed since a point in time.
+func elapsed(now int64, since int64) string {
+	return time.Duration(now - since).Round(time.Second).String()
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,267 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// stallTask describes a task of a tree examined by the watchdog.
+type stallTask struct {
+	kind     string           // type of the task
+	parent   int              // index of the parent task (-1 = root)
+	status   model.TaskStatus // status of the task
+	started  time.Duration    // time since the task has been started (0 = not started)
+	finished time.Duration    // time since the task has finished (0 = not finished)
+	paused   bool             // subtasks are not started
+}
+
+//------------------------------------------------------------------------------
+
+// addStallTasks adds a tree of tasks to a domain.
+func addStallTasks(domain *model.Domain, definitions []stallTask) []*model.Task {
+	now := time.Now()
+	tasks := []*model.Task{}
+
+	for _, definition := range definitions {
+		task := &model.Task{
+			Type:     definition.kind,
+			Domain:   domain.Name,
+			UUID:     uuid.New().String(),
+			Status:   definition.status,
+			Paused:   definition.paused,
+			Subtasks: []string{},
+		}
+		if definition.started > 0 {
+			task.Started = now.Add(-definition.started).UnixNano()
+		}
+		if definition.finished > 0 {
+			task.Finished = now.Add(-definition.finished).UnixNano()
+		}
+		if definition.parent >= 0 {
+			parent := tasks[definition.parent]
+			task.Parent = parent.UUID
+			parent.Subtasks = append(parent.Subtasks, task.UUID)
+		}
+
+		domain.AddTask(task)
+		tasks = append(tasks, task)
+	}
+
+	return tasks
+}
+
+//------------------------------------------------------------------------------
+
+func TestCheckStalls(t *testing.T) {
+	executing, completed := model.TaskStatusExecuting, model.TaskStatusCompleted
+
+	tests := []struct {
+		name     string
+		tasks    []stallTask
+		expected []string
+	}{
+		{
+			name: "progressing",
+			tasks: []stallTask{
+				{kind: "SequentialTask", parent: -1, status: executing, started: time.Minute},
+				{kind: "InstanceTask", parent: 0, status: executing, started: time.Second},
+			},
+			expected: []string{},
+		},
+		{
+			name: "finished subtasks",
+			tasks: []stallTask{
+				{kind: "SequentialTask", parent: -1, status: executing, started: 2 * time.Minute},
+				{kind: "InstanceTask", parent: 0, status: completed, started: 2 * time.Minute, finished: time.Minute},
+			},
+			expected: []string{"finished 0"},
+		},
+		{
+			name: "recently finished subtasks",
+			tasks: []stallTask{
+				{kind: "SequentialTask", parent: -1, status: executing, started: 2 * time.Minute},
+				{kind: "InstanceTask", parent: 0, status: completed, started: 2 * time.Minute, finished: time.Second},
+			},
+			expected: []string{},
+		},
+		{
+			name: "nested finished subtasks",
+			tasks: []stallTask{
+				{kind: "SequentialTask", parent: -1, status: executing, started: 2 * time.Minute},
+				{kind: "ParallelTask", parent: 0, status: executing, started: 2 * time.Minute},
+				{kind: "InstanceTask", parent: 1, status: completed, started: 2 * time.Minute, finished: time.Minute},
+				{kind: "InstanceTask", parent: 1, status: model.TaskStatusFailed, started: 2 * time.Minute, finished: time.Minute},
+			},
+			expected: []string{"finished 1"},
+		},
+		{
+			name: "inactive",
+			tasks: []stallTask{
+				{kind: "SequentialTask", parent: -1, status: executing, started: 2 * time.Hour},
+				{kind: "InstanceTask", parent: 0, status: executing, started: 2 * time.Hour},
+			},
+			expected: []string{"inactive 0"},
+		},
+		{
+			name: "paused",
+			tasks: []stallTask{
+				{kind: "SequentialTask", parent: -1, status: executing, started: 2 * time.Hour, paused: true},
+				{kind: "InstanceTask", parent: 0, status: completed, started: 2 * time.Hour, finished: 2 * time.Hour},
+			},
+			expected: []string{},
+		},
+		{
+			name: "approval",
+			tasks: []stallTask{
+				{kind: "SequentialTask", parent: -1, status: executing, started: 2 * time.Hour},
+				{kind: "ApprovalTask", parent: 0, status: executing, started: 2 * time.Hour},
+			},
+			expected: []string{},
+		},
+		{
+			name: "completed",
+			tasks: []stallTask{
+				{kind: "SequentialTask", parent: -1, status: completed, started: 2 * time.Hour, finished: 2 * time.Hour},
+				{kind: "InstanceTask", parent: 0, status: completed, started: 2 * time.Hour, finished: 2 * time.Hour},
+			},
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			tasks := addStallTasks(domain, test.tasks)
+
+			index := map[string]int{}
+			for i, task := range tasks {
+				index[task.UUID] = i
+			}
+
+			// stalls are reported only once
+			for round := 0; round < 2; round++ {
+				stalls := CheckStalls(domain, time.Hour, false)
+
+				found := []string{}
+				for _, stall := range stalls {
+					found = append(found, fmt.Sprintf("%s %d", stall.Kind, index[stall.Task.UUID]))
+				}
+				sort.Strings(found)
+
+				if !reflect.DeepEqual(found, test.expected) {
+					t.Fatalf("expected stalls %v, got %v", test.expected, found)
+				}
+			}
+
+			events := 0
+			uuids, _ := domain.ListEvents()
+			for _, uuid := range uuids {
+				if event, _ := domain.GetEvent(uuid); event.Type == model.EventTypeStall {
+					events++
+				}
+			}
+			if events != len(test.expected) {
+				t.Errorf("expected %d stall events, got %d", len(test.expected), events)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestCheckStallsRepair(t *testing.T) {
+	executing, completed := model.TaskStatusExecuting, model.TaskStatusCompleted
+
+	tests := []struct {
+		name     string
+		tasks    []stallTask
+		expected []int
+	}{
+		{
+			name: "finished subtasks",
+			tasks: []stallTask{
+				{kind: "SequentialTask", parent: -1, status: executing, started: 2 * time.Minute},
+				{kind: "InstanceTask", parent: 0, status: completed, started: 2 * time.Minute, finished: time.Minute},
+			},
+			expected: []int{0},
+		},
+		{
+			name: "inactive parallel task",
+			tasks: []stallTask{
+				{kind: "ParallelTask", parent: -1, status: executing, started: 2 * time.Hour},
+				{kind: "InstanceTask", parent: 0, status: completed, started: 2 * time.Hour, finished: 2 * time.Hour},
+				{kind: "InstanceTask", parent: 0, status: model.TaskStatusInitial},
+			},
+			expected: []int{2},
+		},
+		{
+			name: "inactive sequential task",
+			tasks: []stallTask{
+				{kind: "SequentialTask", parent: -1, status: executing, started: 2 * time.Hour},
+				{kind: "SequentialTask", parent: 0, status: executing, started: 2 * time.Hour},
+				{kind: "InstanceTask", parent: 1, status: model.TaskStatusInitial},
+			},
+			expected: []int{1},
+		},
+		{
+			name: "inactive operation",
+			tasks: []stallTask{
+				{kind: "SequentialTask", parent: -1, status: executing, started: 2 * time.Hour},
+				{kind: "InstanceTask", parent: 0, status: executing, started: 2 * time.Hour},
+			},
+			expected: []int{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := newTestDomain(t, map[string][]string{})
+			defer model.GetModel().DeleteDomain(domain.Name)
+
+			tasks := addStallTasks(domain, test.tasks)
+
+			// collect the re-emitted execution events
+			done := make(chan bool)
+			triggered := []string{}
+			go func() {
+				CheckStalls(domain, time.Hour, true)
+				close(done)
+			}()
+
+		collect:
+			for {
+				select {
+				case event := <-GetEventChannel():
+					triggered = append(triggered, event.Task)
+				case <-done:
+					break collect
+				case <-time.After(time.Second):
+					t.Fatalf("watchdog has not returned")
+				}
+			}
+
+			expected := []string{}
+			for _, index := range test.expected {
+				expected = append(expected, tasks[index].UUID)
+			}
+			sort.Strings(expected)
+			sort.Strings(triggered)
+
+			if !reflect.DeepEqual(triggered, expected) {
+				t.Errorf("expected events for %v, got %v", expected, triggered)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
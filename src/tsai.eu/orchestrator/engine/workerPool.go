@@ -0,0 +1,359 @@
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+
+// Job is an event handler of a task waiting to be executed by the worker pool.
+type Job struct {
+	Task       *model.Task // task to be handled
+	Handler    func()      // event handler of the task
+	Domain     string      // domain of the task
+	Controller string      // type of the controller invoked by the handler ("" if none)
+	Control    bool        // handler of a timeout or termination which is not subject to the limits
+	Queued     time.Time   // time at which the job has been queued
+}
+
+// WorkerPool executes jobs with a fixed number of workers while respecting the
+// concurrency limits per domain and per controller type. Handlers of the same
+// task are never executed concurrently.
+type WorkerPool struct {
+	sync.Mutex
+	Cond             *sync.Cond      // signals changes of the queue
+	Workers          int             // number of workers
+	DomainLimit      int             // default limit of concurrent jobs per domain (0 = unlimited)
+	ControllerLimit  int             // default limit of concurrent jobs per controller type (0 = unlimited)
+	DomainLimits     map[string]int  // limits of specific domains
+	ControllerLimits map[string]int  // limits of specific controller types
+	Queue            []*Job          // jobs waiting for execution
+	Tasks            map[string]bool // tasks being handled
+	Domains          map[string]int  // number of running jobs per domain
+	Controllers      map[string]int  // number of running jobs per controller type
+	MaxQueued        int             // maximum length of the queue so far
+	Processed        int64           // number of jobs which have been executed
+	WaitTime         time.Duration   // accumulated time jobs have been waiting in the queue
+}
+
+// PoolStatistics provides a snapshot of the metrics of the worker pool.
+type PoolStatistics struct {
+	Workers            int            `yaml:"workers"`            // number of workers
+	Running            int            `yaml:"running"`            // number of running jobs
+	Queued             int            `yaml:"queued"`             // number of queued jobs
+	MaxQueued          int            `yaml:"maxQueued"`          // maximum length of the queue so far
+	Processed          int64          `yaml:"processed"`          // number of jobs which have been executed
+	AverageWait        string         `yaml:"averageWait"`        // average time jobs have been waiting in the queue
+	QueuedDomains      map[string]int `yaml:"queuedDomains"`      // number of queued jobs per domain
+	RunningDomains     map[string]int `yaml:"runningDomains"`     // number of running jobs per domain
+	RunningControllers map[string]int `yaml:"runningControllers"` // number of running jobs per controller type
+}
+
+var pool *WorkerPool
+var poolOnce sync.Once
+
+//------------------------------------------------------------------------------
+
+// GetWorkerPool initialises and returns the worker pool.
+func GetWorkerPool() *WorkerPool {
+
+	// initialise singleton once
+	poolOnce.Do(func() {
+		pool = NewWorkerPool(util.Workers(), util.DomainWorkers(), util.ControllerWorkers())
+	})
+
+	return pool
+}
+
+//------------------------------------------------------------------------------
+
+// NewWorkerPool creates a worker pool and starts its workers.
+func NewWorkerPool(workers int, domainLimit int, controllerLimit int) *WorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+
+	p := WorkerPool{
+		Workers:          workers,
+		DomainLimit:      domainLimit,
+		ControllerLimit:  controllerLimit,
+		DomainLimits:     map[string]int{},
+		ControllerLimits: map[string]int{},
+		Queue:            []*Job{},
+		Tasks:            map[string]bool{},
+		Domains:          map[string]int{},
+		Controllers:      map[string]int{},
+	}
+	p.Cond = sync.NewCond(&p)
+
+	// start the workers
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+
+	return &p
+}
+
+//------------------------------------------------------------------------------
+
+// Submit queues an event handler of a task for execution.
+func (p *WorkerPool) Submit(task *model.Task, handler func()) {
+	p.submit(&Job{
+		Task:       task,
+		Handler:    handler,
+		Domain:     task.Domain,
+		Controller: determineControllerType(task),
+		Queued:     time.Now(),
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// SubmitControl queues the timeout or termination handler of a task. It is
+// not subject to the concurrency limits since it does not invoke any
+// controller but it waits for the running handler of the task.
+func (p *WorkerPool) SubmitControl(task *model.Task, handler func()) {
+	p.submit(&Job{
+		Task:    task,
+		Handler: handler,
+		Domain:  task.Domain,
+		Control: true,
+		Queued:  time.Now(),
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// submit adds a job to the queue.
+func (p *WorkerPool) submit(job *Job) {
+	p.Lock()
+	p.Queue = append(p.Queue, job)
+	if len(p.Queue) > p.MaxQueued {
+		p.MaxQueued = len(p.Queue)
+	}
+	p.Unlock()
+
+	p.Cond.Broadcast()
+}
+
+//------------------------------------------------------------------------------
+
+// SetDomainLimit defines the limit of concurrent jobs of a domain (0 = unlimited).
+func (p *WorkerPool) SetDomainLimit(domain string, limit int) error {
+	if limit < 0 {
+		return errors.New("invalid limit")
+	}
+
+	p.Lock()
+	p.DomainLimits[domain] = limit
+	p.Unlock()
+
+	p.Cond.Broadcast()
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// SetControllerLimit defines the limit of concurrent jobs of a controller type (0 = unlimited).
+func (p *WorkerPool) SetControllerLimit(controller string, limit int) error {
+	if limit < 0 {
+		return errors.New("invalid limit")
+	}
+
+	p.Lock()
+	p.ControllerLimits[controller] = limit
+	p.Unlock()
+
+	p.Cond.Broadcast()
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// Statistics provides a snapshot of the metrics of the worker pool.
+func (p *WorkerPool) Statistics() PoolStatistics {
+	p.Lock()
+	defer p.Unlock()
+
+	stats := PoolStatistics{
+		Workers:            p.Workers,
+		Running:            len(p.Tasks),
+		Queued:             len(p.Queue),
+		MaxQueued:          p.MaxQueued,
+		Processed:          p.Processed,
+		AverageWait:        "0s",
+		QueuedDomains:      map[string]int{},
+		RunningDomains:     map[string]int{},
+		RunningControllers: map[string]int{},
+	}
+
+	if p.Processed > 0 {
+		stats.AverageWait = (p.WaitTime / time.Duration(p.Processed)).String()
+	}
+
+	for _, job := range p.Queue {
+		stats.QueuedDomains[job.Domain]++
+	}
+	for domain, count := range p.Domains {
+		stats.RunningDomains[domain] = count
+	}
+	for controller, count := range p.Controllers {
+		stats.RunningControllers[controller] = count
+	}
+
+	return stats
+}
+
+//------------------------------------------------------------------------------
+
+// work executes jobs until the program terminates.
+func (p *WorkerPool) work() {
+	for {
+		job := p.next()
+
+		handle(job.Task, job.Handler)
+
+		p.done(job)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// next waits for the first queued job which may be executed and reserves the
+// required capacity.
+func (p *WorkerPool) next() *Job {
+	p.Lock()
+	defer p.Unlock()
+
+	for {
+		for index, job := range p.Queue {
+			if !p.isExecutable(job) {
+				continue
+			}
+
+			// remove job from the queue
+			p.Queue = append(p.Queue[:index], p.Queue[index+1:]...)
+
+			// reserve capacity
+			p.WaitTime += time.Since(job.Queued)
+			p.Tasks[job.Task.UUID] = true
+			if job.Control {
+				return job
+			}
+
+			p.Domains[job.Domain]++
+			if job.Controller != "" {
+				p.Controllers[job.Controller]++
+			}
+
+			return job
+		}
+
+		p.Cond.Wait()
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// done releases the capacity reserved by a job.
+func (p *WorkerPool) done(job *Job) {
+	p.Lock()
+
+	p.Processed++
+
+	delete(p.Tasks, job.Task.UUID)
+
+	if job.Control {
+		p.Unlock()
+		p.Cond.Broadcast()
+		return
+	}
+
+	p.Domains[job.Domain]--
+	if p.Domains[job.Domain] == 0 {
+		delete(p.Domains, job.Domain)
+	}
+
+	if job.Controller != "" {
+		p.Controllers[job.Controller]--
+		if p.Controllers[job.Controller] == 0 {
+			delete(p.Controllers, job.Controller)
+		}
+	}
+
+	p.Unlock()
+
+	p.Cond.Broadcast()
+}
+
+//------------------------------------------------------------------------------
+
+// isExecutable determines if a job may be executed without exceeding a limit.
+func (p *WorkerPool) isExecutable(job *Job) bool {
+	// handlers of the same task are executed one after the other
+	if p.Tasks[job.Task.UUID] {
+		return false
+	}
+
+	// timeouts and terminations are not held back by the limits
+	if job.Control {
+		return true
+	}
+
+	// check limit of the domain
+	limit, found := p.DomainLimits[job.Domain]
+	if !found {
+		limit = p.DomainLimit
+	}
+	if limit > 0 && p.Domains[job.Domain] >= limit {
+		return false
+	}
+
+	// check limit of the controller type
+	if job.Controller == "" {
+		return true
+	}
+
+	limit, found = p.ControllerLimits[job.Controller]
+	if !found {
+		limit = p.ControllerLimit
+	}
+	if limit > 0 && p.Controllers[job.Controller] >= limit {
+		return false
+	}
+
+	return true
+}
+
+//------------------------------------------------------------------------------
+
+// determineControllerType determines the type of the controller invoked by a
+// task. Only transition tasks invoke controllers.
+func determineControllerType(task *model.Task) string {
+	if task.Type != "TransitionTask" {
+		return ""
+	}
+
+	domain, err := model.GetModel().GetDomain(task.Domain)
+	if err != nil {
+		return ""
+	}
+
+	component, err := domain.GetComponent(task.Component)
+	if err != nil {
+		return ""
+	}
+
+	return component.Type
+}
+
+//------------------------------------------------------------------------------
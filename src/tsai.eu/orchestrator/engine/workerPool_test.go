@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// waitFor waits until a condition holds and fails the test after five seconds.
+func waitFor(t *testing.T, description string, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", description)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestWorkerPoolLimits(t *testing.T) {
+	domain := newTestDomain(t, map[string][]string{"app": {}})
+	defer model.GetModel().DeleteDomain(domain.Name)
+
+	addTestInstance(t, domain, "app", model.ActiveState, "")
+
+	// transition tasks of the component invoke the test controller
+	first, second, other, control := newTestTask(t, domain, ""), newTestTask(t, domain, ""), newTestTask(t, domain, ""), newTestTask(t, domain, "")
+	for _, task := range []*model.Task{first, second} {
+		task.Type = "TransitionTask"
+		task.Component = "app"
+	}
+
+	release := make(chan bool)
+	handler := func() { <-release }
+
+	pool := NewWorkerPool(4, 2, 0)
+	pool.SetControllerLimit("test", 1)
+
+	running := func(expected int, queued int) func() bool {
+		return func() bool {
+			statistics := pool.Statistics()
+			return statistics.Running == expected && statistics.Queued == queued
+		}
+	}
+
+	// the second transition task exceeds the limit of the controller type
+	pool.Submit(first, handler)
+	pool.Submit(second, handler)
+	pool.Submit(other, handler)
+	waitFor(t, "controller limit", running(2, 1))
+
+	if statistics := pool.Statistics(); statistics.RunningControllers["test"] != 1 || statistics.RunningDomains[domain.Name] != 2 {
+		t.Errorf("unexpected statistics %v", statistics)
+	}
+
+	// handlers of the same task are not executed concurrently
+	pool.Submit(other, handler)
+	waitFor(t, "task serialisation", running(2, 2))
+
+	// control handlers are not subject to the limit of the domain
+	pool.SubmitControl(control, handler)
+	waitFor(t, "control handler", running(3, 2))
+
+	for index := 0; index < 5; index++ {
+		release <- true
+	}
+	waitFor(t, "all handlers", running(0, 0))
+
+	if statistics := pool.Statistics(); statistics.Processed != 5 {
+		t.Errorf("unexpected statistics %v", statistics)
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,73 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+
+// CommandRunner executes hooks as local commands. The configuration of the
+// component is passed as yaml on stdin, the context of the hook via
+// environment variables.
+type CommandRunner struct {
+}
+
+//------------------------------------------------------------------------------
+
+// Run executes the command of a hook.
+func (runner CommandRunner) Run(hook *model.Hook, configuration *model.ComponentConfiguration) error {
+	if len(hook.Command) == 0 {
+		return errors.Errorf("hook '%s' does not define a command", hook.Name)
+	}
+
+	// limit the duration of the command
+	ctx := configuration.GetContext()
+	if hook.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(hook.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	// prepare the command
+	input, err := util.ConvertToYAML(configuration)
+	if err != nil {
+		return err
+	}
+
+	var output bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.Env = append(os.Environ(),
+		"HOOK_NAME="+hook.Name,
+		"HOOK_ON="+hook.On,
+		"HOOK_DOMAIN="+configuration.Domain,
+		"HOOK_COMPONENT="+configuration.Component,
+		"HOOK_INSTANCE="+configuration.Instance,
+	)
+
+	// execute the command
+	err = cmd.Run()
+	if err != nil {
+		if message := strings.TrimSpace(output.String()); message != "" {
+			return errors.Wrapf(err, "hook '%s' failed: %s", hook.Name, message)
+		}
+		return errors.Wrapf(err, "hook '%s' failed", hook.Name)
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,76 @@
+package hook
+
+import (
+	"errors"
+	"sync"
+
+	"tsai.eu/orchestrator/model"
+)
+
+//------------------------------------------------------------------------------
+
+// Runner executes the hooks of a specific type
+type Runner interface {
+	Run(hook *model.Hook, configuration *model.ComponentConfiguration) error
+}
+
+//------------------------------------------------------------------------------
+
+var runners map[string]Runner
+var runnersLock sync.RWMutex
+
+var once sync.Once
+
+//------------------------------------------------------------------------------
+
+// initRunners registers the built-in runners.
+func initRunners() {
+	// initialise singleton once
+	once.Do(func() {
+		runners = map[string]Runner{}
+
+		runners[model.HookCommand] = CommandRunner{}
+		runners[model.HookHTTP] = HTTPRunner{}
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// RegisterRunner registers a runner for a specific hook type.
+func RegisterRunner(hookType string, runner Runner) error {
+	initRunners()
+
+	// check parameters
+	if runner == nil {
+		return errors.New("undefined runner")
+	}
+
+	// register runner
+	runnersLock.Lock()
+	runners[hookType] = runner
+	runnersLock.Unlock()
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// GetRunner retrieves a runner for a specific hook type.
+func GetRunner(hookType string) (Runner, error) {
+	initRunners()
+
+	// determine runner
+	runnersLock.RLock()
+	runner, found := runners[hookType]
+	runnersLock.RUnlock()
+
+	if !found {
+		return nil, errors.New("unknown hook type: " + hookType)
+	}
+
+	// success
+	return runner, nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,74 @@
+package hook
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/model"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+
+// HTTPRunner executes hooks by calling http endpoints. The configuration of
+// the component is passed as yaml in the body of the request.
+type HTTPRunner struct {
+}
+
+//------------------------------------------------------------------------------
+
+// Run calls the endpoint of a hook and expects a successful status code.
+func (runner HTTPRunner) Run(hook *model.Hook, configuration *model.ComponentConfiguration) error {
+	if hook.URL == "" {
+		return errors.Errorf("hook '%s' does not define an url", hook.Name)
+	}
+
+	method := hook.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	// limit the duration of the call
+	ctx := configuration.GetContext()
+	if hook.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(hook.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	// prepare the request
+	body, err := util.ConvertToYAML(configuration)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(method, hook.URL, strings.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "hook '%s' failed", hook.Name)
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/x-yaml")
+	request.Header.Set("X-Hook-Name", hook.Name)
+	request.Header.Set("X-Hook-On", hook.On)
+
+	// call the endpoint
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "hook '%s' failed", hook.Name)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		message, _ := ioutil.ReadAll(response.Body)
+		return errors.Errorf("hook '%s' failed with status %d: %s", hook.Name, response.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
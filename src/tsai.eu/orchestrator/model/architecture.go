@@ -14,6 +14,7 @@ import (
 // Attributes:
 //   - Name
 //   - Services
+//   - Gates
 //
 // Functions:
 //   - NewArchitecture
@@ -21,6 +22,7 @@ import (
 //   - architecture.Show
 //   - architecture.Load
 //   - architecture.Save
+//   - architecture.Copy
 //
 //   - architecture.ListServices
 //   - architecture.GetService
@@ -57,8 +59,9 @@ func (m *ServiceMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 // Architecture describes a desired configuration of services within a domain.
 type Architecture struct {
-	Name     string     `yaml:"name"`     // name of the architecture
-	Services ServiceMap `yaml:"services"` // map of services (components)
+	Name     string          `yaml:"name"`            // name of the architecture
+	Services ServiceMap      `yaml:"services"`        // map of services (components)
+	Gates    []*ApprovalGate `yaml:"gates,omitempty"` // manual approvals between services (optional)
 }
 
 //------------------------------------------------------------------------------
@@ -69,6 +72,7 @@ func NewArchitecture(name string) (*Architecture, error) {
 
 	architecture.Name = name
 	architecture.Services = ServiceMap{Map: map[string]*Service{}}
+	architecture.Gates = []*ApprovalGate{}
 
 	// success
 	return &architecture, nil
@@ -97,6 +101,46 @@ func (architecture *Architecture) Load(filename string) error {
 
 //------------------------------------------------------------------------------
 
+// Copy creates a deep copy of the architecture
+func (architecture *Architecture) Copy() *Architecture {
+	result, _ := NewArchitecture(architecture.Name)
+
+	architecture.Services.RLock()
+	defer architecture.Services.RUnlock()
+
+	for name, service := range architecture.Services.Map {
+		duplicate, _ := NewService(service.Name)
+
+		if service.Strategy != nil {
+			strategy := *service.Strategy
+			duplicate.Strategy = &strategy
+		}
+		if service.Release != nil {
+			release := *service.Release
+			duplicate.Release = &release
+		}
+
+		service.Setups.RLock()
+		for version, setup := range service.Setups.Map {
+			s := *setup
+			duplicate.Setups.Map[version] = &s
+		}
+		service.Setups.RUnlock()
+
+		result.Services.Map[name] = duplicate
+	}
+
+	for _, gate := range architecture.Gates {
+		duplicate := *gate
+		duplicate.After = append([]string{}, gate.After...)
+		result.Gates = append(result.Gates, &duplicate)
+	}
+
+	return result
+}
+
+//------------------------------------------------------------------------------
+
 // ListServices lists all services of a domain
 func (architecture *Architecture) ListServices() ([]string, error) {
 	// collect names
@@ -179,6 +223,8 @@ func (architecture *Architecture) DeleteService(name string) error {
 // Attributes:
 //   - Name
 //   - Setups
+//   - Strategy
+//   - Release
 //
 // Functions:
 //   - NewService
@@ -222,8 +268,10 @@ func (m *SetupMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 // Service describes all desired configurations for a component within a domain.
 type Service struct {
-	Name   string   `yaml:"name"`   // name of component
-	Setups SetupMap `yaml:"setups"` // configuration of component version
+	Name     string          `yaml:"name"`               // name of component
+	Setups   SetupMap        `yaml:"setups"`             // configuration of component version
+	Strategy *UpdateStrategy `yaml:"strategy,omitempty"` // replacement of instances (optional: all at once)
+	Release  *ReleasePolicy  `yaml:"release,omitempty"`  // release of new versions (optional: immediately)
 }
 
 //------------------------------------------------------------------------------
@@ -1,6 +1,10 @@
 package model
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/google/uuid"
@@ -45,10 +49,6 @@ const ResettingState string = "resetting"
 
 //------------------------------------------------------------------------------
 
-// TransitionTable is map of allowed transitions
-var transitionTable map[string]map[string]string
-var transitionTableInit sync.Once
-
 // IsValidStateOrTransition determines if a string resembles a valid state or transition.
 func IsValidStateOrTransition(state string) bool {
 	switch state {
@@ -58,12 +58,21 @@ func IsValidStateOrTransition(state string) bool {
 	return false
 }
 
-// IsValidState determines if a string resembles a valid state.
+// IsValidState determines if a string resembles a valid state of the built-in
+// lifecycle or of any registered state machine.
 func IsValidState(state string) bool {
-	switch state {
-	case InitialState, InactiveState, ActiveState, FailureState:
+	if DefaultStateMachine().IsValidState(state) {
 		return true
 	}
+
+	stateMachinesLock.RLock()
+	defer stateMachinesLock.RUnlock()
+
+	for _, machine := range stateMachines {
+		if machine.IsValidState(state) {
+			return true
+		}
+	}
 	return false
 }
 
@@ -76,48 +85,32 @@ func IsValidTransition(transition string) bool {
 	return false
 }
 
-// GetTransition determines the required transition between a current state and a target state.
-func GetTransition(currentState string, targetState string) (string, error) {
-	// initialise singleton once
-	transitionTableInit.Do(func() {
-		transitionTable = map[string]map[string]string{}
-
-		transitionTable[InitialState] = map[string]string{
-			InitialState:  "none",
-			InactiveState: "create",
-			ActiveState:   "create",
-		}
-		transitionTable[InactiveState] = map[string]string{
-			InitialState:  "destroy",
-			InactiveState: "none",
-			ActiveState:   "start",
-		}
-		transitionTable[ActiveState] = map[string]string{
-			InitialState:  "stop",
-			InactiveState: "stop",
-			ActiveState:   "none",
-		}
-		transitionTable[FailureState] = map[string]string{
-			InitialState:  "reset",
-			InactiveState: "reset",
-			ActiveState:   "reset",
-		}
-	})
-
-	// check parameters
-	if !IsValidState(currentState) || !IsValidState(targetState) {
-		return "", errors.New("invalid state")
-	}
+// GetTransitionResult determines the state resulting from a transition of the built-in lifecycle.
+func GetTransitionResult(currentState string, transition string) (string, error) {
+	return DefaultStateMachine().GetTransitionResult(currentState, transition)
+}
 
-	// determine transition
-	transition, err := GetTransition(currentState, targetState)
+// GetTransitions determines the sequence of transitions of the built-in
+// lifecycle required to move from a current state to a target state.
+func GetTransitions(currentState string, targetState string) ([]string, error) {
+	return DefaultStateMachine().GetTransitions(currentState, targetState)
+}
 
+// GetTransition determines the next transition required between a current state and a target state.
+func GetTransition(currentState string, targetState string) (string, error) {
+	// determine all transitions
+	transitions, err := GetTransitions(currentState, targetState)
 	if err != nil {
-		return "", errors.New("invalid transition")
+		return "", err
+	}
+
+	// no transition is required
+	if len(transitions) == 0 {
+		return "none", nil
 	}
 
 	//success
-	return transition, nil
+	return transitions[0], nil
 }
 
 //------------------------------------------------------------------------------
@@ -129,6 +122,11 @@ func GetTransition(currentState string, targetState string) (string, error) {
 //   - Type
 //   - State
 //   - Endpoint
+//   - Endpoints
+//   - Active
+//   - Previous
+//   - Canary
+//   - Weights
 //   - Instances
 //
 // Functions:
@@ -200,11 +198,15 @@ func (m *InstanceMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 // Component describes all desired configurations for a component within a domain.
 type Component struct {
-	Name      string      `yaml:"name"`      // name of component
-	Type      string      `yaml:"type"`      // type of component
-	Endpoint  string      `yaml:"endpoint"`  // endpoint of component
-	Endpoints EndpointMap `yaml:"endpoints"` // endpoint of component versions
-	Instances InstanceMap `yaml:"instances"` // instances of component
+	Name      string              `yaml:"name"`               // name of component
+	Type      string              `yaml:"type"`               // type of component
+	Endpoint  string              `yaml:"endpoint"`           // endpoint of component
+	Endpoints EndpointMap         `yaml:"endpoints"`          // endpoint of component versions
+	Active    string              `yaml:"active,omitempty"`   // version the endpoint of the component refers to ("" = latest reported)
+	Previous  string              `yaml:"previous,omitempty"` // version kept for a rollback of a blue/green release
+	Canary    string              `yaml:"canary,omitempty"`   // version of a canary release
+	Weights   []*WeightedEndpoint `yaml:"weights,omitempty"`  // shares of the traffic during a canary release
+	Instances InstanceMap         `yaml:"instances"`          // instances of component
 }
 
 //------------------------------------------------------------------------------
@@ -283,8 +285,12 @@ func (component *Component) GetEndpoint(name string) (string, error) {
 // GetEndpoints retrieves a map of endpoints
 func (component *Component) GetEndpoints() map[string]string {
 	// determine instance
+	endpoints := map[string]string{}
+
 	component.Endpoints.RLock()
-	endpoints := component.Endpoints.Map
+	for name, endpoint := range component.Endpoints.Map {
+		endpoints[name] = endpoint
+	}
 	component.Endpoints.RUnlock()
 
 	// success
@@ -296,9 +302,9 @@ func (component *Component) GetEndpoints() map[string]string {
 // AddEndpoint adds/overwrites an endpoint of a component
 func (component *Component) AddEndpoint(name string, endpoint string) error {
 	// set endpoint
-	component.Endpoints.RLock()
+	component.Endpoints.Lock()
 	component.Endpoints.Map[name] = endpoint
-	component.Endpoints.RUnlock()
+	component.Endpoints.Unlock()
 
 	// success
 	return nil
@@ -414,6 +420,7 @@ func (component *Component) DeleteInstance(uuid string) error {
 //   - Version
 //   - State
 //   - Endpoint
+//   - Fingerprint
 //
 // Functions:
 //   - NewInstance
@@ -452,11 +459,12 @@ func (m *DependencyEndpointMap) UnmarshalYAML(unmarshal func(interface{}) error)
 
 // Instance describes all desired configurations for a component within a domain.
 type Instance struct {
-	UUID         string                `yaml:"uuid"`         // uuid of the instance
-	Version      string                `yaml:"version"`      // version of the instance
-	State        string                `yaml:"state"`        // state of the instance
-	Endpoint     string                `yaml:"endpoint"`     // state of the instance
-	Dependencies DependencyEndpointMap `yaml:"dependencies"` // endpoints of the dependencies
+	UUID         string                `yaml:"uuid"`                  // uuid of the instance
+	Version      string                `yaml:"version"`               // version of the instance
+	State        string                `yaml:"state"`                 // state of the instance
+	Endpoint     string                `yaml:"endpoint"`              // state of the instance
+	Dependencies DependencyEndpointMap `yaml:"dependencies"`          // endpoints of the dependencies
+	Fingerprint  string                `yaml:"fingerprint,omitempty"` // fingerprint of the applied configuration
 }
 
 //------------------------------------------------------------------------------
@@ -550,9 +558,17 @@ func (instance *Instance) DeleteDependency(name string) {
 
 //------------------------------------------------------------------------------
 
-// GetDependencies retrieves all currently defined dependency endpoints
+// GetDependencies retrieves a copy of all currently defined dependency endpoints
 func (instance *Instance) GetDependencies() map[string]string {
-	return instance.Dependencies.Map
+	instance.Dependencies.RLock()
+	defer instance.Dependencies.RUnlock()
+
+	dependencies := map[string]string{}
+	for name, endpoint := range instance.Dependencies.Map {
+		dependencies[name] = endpoint
+	}
+
+	return dependencies
 }
 
 //------------------------------------------------------------------------------
@@ -560,7 +576,7 @@ func (instance *Instance) GetDependencies() map[string]string {
 // SetDependencies updates the dependencies of an instance
 func (instance *Instance) SetDependencies(dependencies map[string]string) {
 	instance.Dependencies.Lock()
-	instance.Dependencies = DependencyEndpointMap{Map: dependencies}
+	instance.Dependencies.Map = dependencies
 	instance.Dependencies.Unlock()
 }
 
@@ -579,7 +595,11 @@ func DetermineDependencies(domain *Domain, component *Component, instance *Insta
 	list, _ := variant.ListDependencies()
 	for _, name := range list {
 		dependency, _ := variant.GetDependency(name)
-		serviceComponent, _ := domain.GetComponent(dependency.Component)
+		serviceComponent, err := domain.GetComponent(dependency.Component)
+		if err != nil {
+			dependencies[name] = ""
+			continue
+		}
 		dependencies[name], _ = serviceComponent.GetEndpoint(dependency.Version)
 	}
 
@@ -588,3 +608,36 @@ func DetermineDependencies(domain *Domain, component *Component, instance *Insta
 }
 
 //------------------------------------------------------------------------------
+
+// DetermineFingerprint calculates a fingerprint of the effective configuration
+// of an instance, i.e. the configuration of its template variant together with
+// the endpoints of its dependencies.
+func DetermineFingerprint(domain *Domain, component *Component, instance *Instance) string {
+	hash := sha256.New()
+
+	// configuration of the template variant
+	template, err := domain.GetTemplate(component.Name)
+	if err == nil {
+		variant, err := template.GetVariant(instance.Version)
+		if err == nil {
+			fmt.Fprintf(hash, "%s\n", variant.Configuration)
+		}
+	}
+
+	// endpoints of the dependencies
+	dependencies := DetermineDependencies(domain, component, instance)
+
+	names := []string{}
+	for name := range dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(hash, "%s=%s\n", name, dependencies[name])
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+//------------------------------------------------------------------------------
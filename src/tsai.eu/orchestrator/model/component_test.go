@@ -0,0 +1,97 @@
+package model
+
+import (
+	"testing"
+)
+
+//------------------------------------------------------------------------------
+
+// newFingerprintDomain creates a domain with a component "app" depending on a
+// component "db" and returns the instance of "app".
+func newFingerprintDomain() (*Domain, *Component, *Instance) {
+	domain, _ := NewDomain("fingerprint")
+
+	for _, name := range []string{"app", "db", "web"} {
+		template, _ := NewTemplate(name, "test")
+		for _, version := range []string{"1.0.0", "2.0.0"} {
+			variant, _ := NewVariant(version, name+"-"+version)
+			if name == "app" {
+				dependency, _ := NewDependency("database", "service", "db", "1.0.0")
+				variant.AddDependency(dependency)
+			}
+			template.AddVariant(variant)
+		}
+		domain.AddTemplate(template)
+
+		component, _ := NewComponent(name, "test")
+		component.AddEndpoint("1.0.0", name+"-1")
+		domain.AddComponent(component)
+	}
+
+	component, _ := domain.GetComponent("app")
+	instance, _ := NewInstance("1.0.0")
+	instance.State = ActiveState
+	component.AddInstance(instance)
+
+	return domain, component, instance
+}
+
+//------------------------------------------------------------------------------
+
+func TestDetermineFingerprint(t *testing.T) {
+	tests := []struct {
+		name          string
+		state         string // new state of the instance
+		version       string // new version of the instance
+		configuration string // version of the variant whose configuration changes
+		endpoint      string // component publishing a new endpoint
+		removed       string // component which is removed
+		changed       bool
+	}{
+		{name: "unchanged", changed: false},
+		{name: "changed state", state: InactiveState, changed: false},
+		{name: "changed configuration", configuration: "1.0.0", changed: true},
+		{name: "changed configuration of another version", configuration: "2.0.0", changed: false},
+		{name: "changed version", version: "2.0.0", changed: true},
+		{name: "changed endpoint of a dependency", endpoint: "db", changed: true},
+		{name: "removed dependency", removed: "db", changed: true},
+		{name: "changed endpoint of an unrelated component", endpoint: "web", changed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain, component, instance := newFingerprintDomain()
+
+			before := DetermineFingerprint(domain, component, instance)
+			if before != DetermineFingerprint(domain, component, instance) {
+				t.Fatalf("fingerprint is not deterministic")
+			}
+
+			if test.state != "" {
+				instance.State = test.state
+			}
+			if test.version != "" {
+				instance.Version = test.version
+			}
+			if test.configuration != "" {
+				template, _ := domain.GetTemplate("app")
+				variant, _ := template.GetVariant(test.configuration)
+				variant.Configuration = "changed"
+			}
+			if test.endpoint != "" {
+				dependency, _ := domain.GetComponent(test.endpoint)
+				dependency.AddEndpoint("1.0.0", test.endpoint+"-2")
+			}
+			if test.removed != "" {
+				domain.DeleteComponent(test.removed)
+			}
+
+			after := DetermineFingerprint(domain, component, instance)
+			if (before != after) != test.changed {
+				t.Errorf("expected change %v, got %s -> %s", test.changed, before, after)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -1,5 +1,9 @@
 package model
 
+import (
+	"context"
+)
+
 //------------------------------------------------------------------------------
 
 // ComponentConfiguration object passed to controller.
@@ -11,6 +15,18 @@ type ComponentConfiguration struct {
 	Endpoints map[string]string                 // endpoints of the instances
 	State     string                            // desired state
 	Instances map[string]*InstanceConfiguration // configurations of the instances
+	Context   context.Context                   `yaml:"-" json:"-"` // signals the cancellation of the operation
+}
+
+//------------------------------------------------------------------------------
+
+// GetContext provides the context of the operation which is cancelled if the
+// task triggering the operation has been cancelled.
+func (configuration *ComponentConfiguration) GetContext() context.Context {
+	if configuration.Context == nil {
+		return context.Background()
+	}
+	return configuration.Context
 }
 
 // InstanceConfiguration describes the current configuration of an instance.
@@ -42,6 +58,9 @@ func GetConfiguration(domainName string, componentName string, instanceUUID stri
 	component, _ := domain.GetComponent(componentName)
 	template, _ := domain.GetTemplate(componentName)
 
+	statusLock.RLock()
+	defer statusLock.RUnlock()
+
 	configuration.Domain = domainName
 	configuration.Component = componentName
 	configuration.Instance = instanceUUID
@@ -71,8 +90,12 @@ func GetConfiguration(domainName string, componentName string, instanceUUID stri
 
 		for _, dependencyName := range dependencies {
 			dependency, _ := variant.GetDependency(dependencyName)
-			service, _ := domain.GetComponent(dependency.Name)
-			endpoint, _ := service.GetEndpoint(dependency.Version)
+
+			endpoint := ""
+			service, err := domain.GetComponent(dependency.Component)
+			if err == nil {
+				endpoint, _ = service.GetEndpoint(dependency.Version)
+			}
 
 			configurationInstance.Dependencies[dependency.Name] = &ConfigurationDependency{
 				Name:      dependency.Name,
@@ -13,11 +13,16 @@ import (
 //
 // Attributes:
 //   - Name
+//   - Architecture
+//   - Reconcile
+//   - Applied
+//   - Rollback
 //   - Templates
 //   - Architectures
 //   - Components
 //   - Tasks
 //   - Events
+//   - Schedules
 //
 // Functions:
 //   - NewDomain
@@ -50,6 +55,11 @@ import (
 //   - domain.GetEvent
 //   - domain.AddEvent
 //   - domain.DeleteEvent
+//
+//   - domain.ListSchedules
+//   - domain.GetSchedule
+//   - domain.AddSchedule
+//   - domain.DeleteSchedule
 //------------------------------------------------------------------------------
 
 // TemplateMap is a synchronized map for a map of templates
@@ -90,6 +100,20 @@ func (m ArchitectureMap) MarshalYAML() (interface{}, error) {
 	return m.Map, nil
 }
 
+// UnmarshalYAML unmarshals a ArchitectureMap from yaml
+func (m *ArchitectureMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	Map := map[string]*Architecture{}
+
+	err := unmarshal(&Map)
+	if err != nil {
+		return err
+	}
+
+	*m = ArchitectureMap{Map: Map}
+
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // ComponentMap is a synchronized map for a map of components
@@ -103,6 +127,20 @@ func (m ComponentMap) MarshalYAML() (interface{}, error) {
 	return m.Map, nil
 }
 
+// UnmarshalYAML unmarshals a ComponentMap from yaml
+func (m *ComponentMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	Map := map[string]*Component{}
+
+	err := unmarshal(&Map)
+	if err != nil {
+		return err
+	}
+
+	*m = ComponentMap{Map: Map}
+
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // TaskMap is a synchronized map for a map of tasks
@@ -116,6 +154,20 @@ func (m TaskMap) MarshalYAML() (interface{}, error) {
 	return m.Map, nil
 }
 
+// UnmarshalYAML unmarshals a TaskMap from yaml
+func (m *TaskMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	Map := map[string]*Task{}
+
+	err := unmarshal(&Map)
+	if err != nil {
+		return err
+	}
+
+	*m = TaskMap{Map: Map}
+
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // EventMap is a synchronized map for a map of events
@@ -129,16 +181,62 @@ func (m EventMap) MarshalYAML() (interface{}, error) {
 	return m.Map, nil
 }
 
+// UnmarshalYAML unmarshals a EventMap from yaml
+func (m *EventMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	Map := map[string]*Event{}
+
+	err := unmarshal(&Map)
+	if err != nil {
+		return err
+	}
+
+	*m = EventMap{Map: Map}
+
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// ScheduleMap is a synchronized map for a map of schedules
+type ScheduleMap struct {
+	sync.RWMutex `yaml:"mutex,omitempty"` // mutex
+	Map          map[string]*Schedule     `yaml:"map"` // map of schedules
+}
+
+// MarshalYAML marshals a ScheduleMap into yaml
+func (m *ScheduleMap) MarshalYAML() (interface{}, error) {
+	return m.Map, nil
+}
+
+// UnmarshalYAML unmarshals a ScheduleMap from yaml
+func (m *ScheduleMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	Map := map[string]*Schedule{}
+
+	err := unmarshal(&Map)
+	if err != nil {
+		return err
+	}
+
+	m.Map = Map
+
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // Domain describes all artefacts managed with an administrative realm.
 type Domain struct {
 	Name          string          `yaml:"name"`          // name of the domain
+	Architecture  string          `yaml:"architecture"`  // name of the architecture which has been executed last
+	Reconcile     bool            `yaml:"reconcile"`     // automatic correction of drift
+	Applied       *Architecture   `yaml:"applied"`       // copy of the architecture which has been applied successfully last
+	Rollback      bool            `yaml:"rollback"`      // automatic rollback of failed architecture executions
 	Templates     TemplateMap     `yaml:"templates"`     // map of templates
 	Architectures ArchitectureMap `yaml:"architectures"` // map of architectures
 	Components    ComponentMap    `yaml:"components"`    // list of components
 	Tasks         TaskMap         `yaml:"tasks"`         // list of tasks
 	Events        EventMap        `yaml:"events"`        // list of events
+	Schedules     *ScheduleMap    `yaml:"schedules"`     // map of schedules
 }
 
 //------------------------------------------------------------------------------
@@ -148,11 +246,16 @@ func NewDomain(name string) (*Domain, error) {
 	var domain Domain
 
 	domain.Name = name
+	domain.Architecture = ""
+	domain.Reconcile = false
+	domain.Applied = nil
+	domain.Rollback = false
 	domain.Templates = TemplateMap{Map: map[string]*Template{}}
 	domain.Architectures = ArchitectureMap{Map: map[string]*Architecture{}}
 	domain.Components = ComponentMap{Map: map[string]*Component{}}
 	domain.Tasks = TaskMap{Map: map[string]*Task{}}
 	domain.Events = EventMap{Map: map[string]*Event{}}
+	domain.Schedules = &ScheduleMap{Map: map[string]*Schedule{}}
 
 	// success
 	return &domain, nil
@@ -160,6 +263,25 @@ func NewDomain(name string) (*Domain, error) {
 
 //------------------------------------------------------------------------------
 
+// UnmarshalYAML unmarshals a Domain from yaml
+func (domain *Domain) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Domain
+
+	err := unmarshal((*plain)(domain))
+	if err != nil {
+		return err
+	}
+
+	// domains loaded from files without schedules lack the map
+	if domain.Schedules == nil {
+		domain.Schedules = &ScheduleMap{Map: map[string]*Schedule{}}
+	}
+
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
 // Show displays the domain information as json
 func (domain *Domain) Show() (string, error) {
 	return util.ConvertToYAML(domain)
@@ -565,3 +687,82 @@ func (domain *Domain) DeleteEvent(uuid string) error {
 }
 
 //------------------------------------------------------------------------------
+
+// ListSchedules lists all schedules of a domain
+func (domain *Domain) ListSchedules() ([]string, error) {
+	// collect uuids
+	schedules := []string{}
+
+	domain.Schedules.RLock()
+	for uuid := range domain.Schedules.Map {
+		schedules = append(schedules, uuid)
+	}
+	domain.Schedules.RUnlock()
+
+	// success
+	return schedules, nil
+}
+
+//------------------------------------------------------------------------------
+
+// GetSchedule retrieves a schedule by uuid
+func (domain *Domain) GetSchedule(uuid string) (*Schedule, error) {
+	// determine schedule
+	domain.Schedules.RLock()
+	schedule, ok := domain.Schedules.Map[uuid]
+	domain.Schedules.RUnlock()
+
+	if !ok {
+		return nil, errors.New("schedule not found")
+	}
+
+	// success
+	return schedule, nil
+}
+
+//------------------------------------------------------------------------------
+
+// AddSchedule adds a schedule to a domain
+func (domain *Domain) AddSchedule(schedule *Schedule) error {
+	domain.Schedules.Lock()
+	defer domain.Schedules.Unlock()
+
+	// ensure the map of schedules exists
+	if domain.Schedules.Map == nil {
+		domain.Schedules.Map = map[string]*Schedule{}
+	}
+
+	// check if schedule has already been defined
+	if _, ok := domain.Schedules.Map[schedule.UUID]; ok {
+		return errors.New("schedule already exists")
+	}
+
+	domain.Schedules.Map[schedule.UUID] = schedule
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// DeleteSchedule deletes a schedule
+func (domain *Domain) DeleteSchedule(uuid string) error {
+	// determine schedule
+	domain.Schedules.RLock()
+	_, ok := domain.Schedules.Map[uuid]
+	domain.Schedules.RUnlock()
+
+	if !ok {
+		return errors.New("schedule not found")
+	}
+
+	// remove schedule
+	domain.Schedules.Lock()
+	delete(domain.Schedules.Map, uuid)
+	domain.Schedules.Unlock()
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
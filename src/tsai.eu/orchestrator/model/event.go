@@ -25,6 +25,14 @@ const (
 	EventTypeTaskTimeout EventType = "timeout"
 	// EventTypeTaskTermination resembles an event which should trigger termination handling of a task.
 	EventTypeTaskTermination EventType = "termination"
+	// EventTypeDrift resembles an event which records a deviation of the actual from the expected state.
+	EventTypeDrift EventType = "drift"
+	// EventTypeStall resembles an event which records a task which has stopped making progress.
+	EventTypeStall EventType = "stall"
+	// EventTypeLiveness resembles an event which records an instance which has failed its liveness check.
+	EventTypeLiveness EventType = "liveness"
+	// EventTypeError resembles an event which records an event which could not be handled.
+	EventTypeError EventType = "error"
 	// EventTypeTaskUnknown resembles an unknown event.
 	EventTypeTaskUnknown EventType = "unknown"
 )
@@ -42,6 +50,14 @@ func EventType2String(eventType EventType) (string, error) {
 		return "timeout", nil
 	case EventTypeTaskTermination:
 		return "termination", nil
+	case EventTypeDrift:
+		return "drift", nil
+	case EventTypeStall:
+		return "stall", nil
+	case EventTypeLiveness:
+		return "liveness", nil
+	case EventTypeError:
+		return "error", nil
 	}
 	return "", errors.New("unknown type")
 }
@@ -59,6 +75,14 @@ func String2EventType(eventType string) (EventType, error) {
 		return EventTypeTaskTimeout, nil
 	case "termination":
 		return EventTypeTaskTermination, nil
+	case "drift":
+		return EventTypeDrift, nil
+	case "stall":
+		return EventTypeStall, nil
+	case "liveness":
+		return EventTypeLiveness, nil
+	case "error":
+		return EventTypeError, nil
 	}
 	return EventTypeTaskUnknown, errors.New("unknown type")
 }
@@ -73,6 +97,8 @@ func String2EventType(eventType string) (EventType, error) {
 //   - Task
 //   - type
 //   - Source
+//   - Time
+//   - Detail
 //
 // Functions:
 //   - NewEvent
@@ -84,12 +110,13 @@ func String2EventType(eventType string) (EventType, error) {
 
 // Event describes a situation which may trigger further tasks.
 type Event struct {
-	Domain string    `yaml:"domain"` // domain of event
-	UUID   string    `yaml:"uuid"`   // uuid of event
-	Task   string    `yaml:"task"`   // uuid of task
-	Type   EventType `yaml:"type"`   // type of event: "execution", "completion", "failure"
-	Source string    `yaml:"source"` // source of the event (uuid of the task or "")
-	Time   int64     `yaml:"time"`   // time since 1.1.1970 in nsecs
+	Domain string    `yaml:"domain"`           // domain of event
+	UUID   string    `yaml:"uuid"`             // uuid of event
+	Task   string    `yaml:"task"`             // uuid of task
+	Type   EventType `yaml:"type"`             // type of event: "execution", "completion", "failure"
+	Source string    `yaml:"source"`           // source of the event (uuid of the task or "")
+	Time   int64     `yaml:"time"`             // time since 1.1.1970 in nsecs
+	Detail string    `yaml:"detail,omitempty"` // description of the situation (optional)
 }
 
 //------------------------------------------------------------------------------
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+// ApprovalGate
+// ============
+//
+// Attributes:
+//   - Name
+//   - After
+//   - Expiry
+//
+// Functions:
+//   - NewApprovalGate
+//
+//   - gate.Show
+//   - gate.Load
+//   - gate.Save
+//
+//   - gate.Validate
+//------------------------------------------------------------------------------
+
+// ApprovalGate pauses the deployment of an architecture once a set of services
+// has been processed until the changes have been approved manually.
+type ApprovalGate struct {
+	Name   string   `yaml:"name"`   // name of the gate
+	After  []string `yaml:"after"`  // services which need to be approved
+	Expiry int      `yaml:"expiry"` // time after which the approval times out in seconds (0 = never)
+}
+
+//------------------------------------------------------------------------------
+
+// NewApprovalGate creates a new approval gate
+func NewApprovalGate(name string, after []string, expiry int) (*ApprovalGate, error) {
+	var gate ApprovalGate
+
+	gate.Name = name
+	gate.After = after
+	gate.Expiry = expiry
+
+	// success
+	return &gate, gate.Validate()
+}
+
+//------------------------------------------------------------------------------
+
+// Show displays the approval gate information as yaml
+func (gate *ApprovalGate) Show() (string, error) {
+	return util.ConvertToYAML(gate)
+}
+
+//------------------------------------------------------------------------------
+
+// Save writes the approval gate as yaml data to a file
+func (gate *ApprovalGate) Save(filename string) error {
+	return util.SaveYAML(filename, gate)
+}
+
+//------------------------------------------------------------------------------
+
+// Load reads the approval gate from a file
+func (gate *ApprovalGate) Load(filename string) error {
+	return util.LoadYAML(filename, gate)
+}
+
+//------------------------------------------------------------------------------
+
+// Validate checks the consistency of the approval gate.
+func (gate *ApprovalGate) Validate() error {
+	if gate.Name == "" {
+		return errors.New("approval gate requires a name")
+	}
+
+	if len(gate.After) == 0 {
+		return errors.Errorf("approval gate '%s' requires at least one service", gate.Name)
+	}
+
+	if gate.Expiry < 0 {
+		return errors.Errorf("expiry of approval gate '%s' must not be negative", gate.Name)
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
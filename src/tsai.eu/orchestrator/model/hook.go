@@ -0,0 +1,161 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+// Hook
+// ====
+//
+// Attributes:
+//   - Name
+//   - On
+//   - Type
+//   - Command
+//   - URL
+//   - Method
+//   - Timeout
+//   - OnFailure
+//
+// Functions:
+//   - NewHook
+//
+//   - hook.Show
+//   - hook.Load
+//   - hook.Save
+//
+//   - hook.Validate
+//   - hook.Phase
+//   - hook.Transition
+//------------------------------------------------------------------------------
+
+// HookPre indicates that a hook is executed before a transition
+const HookPre string = "pre"
+
+// HookPost indicates that a hook is executed after a transition
+const HookPost string = "post"
+
+// HookCommand indicates that a hook executes a local command
+const HookCommand string = "command"
+
+// HookHTTP indicates that a hook calls an http endpoint
+const HookHTTP string = "http"
+
+// HookAbort indicates that the failure of a hook aborts the instance task
+const HookAbort string = "abort"
+
+// HookWarn indicates that the failure of a hook is merely recorded
+const HookWarn string = "warn"
+
+//------------------------------------------------------------------------------
+
+// Hook describes an action which is executed before or after a transition of
+// the instances of a template variant.
+type Hook struct {
+	Name      string   `yaml:"name"`              // name of the hook
+	On        string   `yaml:"on"`                // phase and transition, e.g. pre-create or post-start
+	Type      string   `yaml:"type"`              // type of the hook (command/http)
+	Command   []string `yaml:"command,omitempty"` // command and arguments of a command hook
+	URL       string   `yaml:"url,omitempty"`     // url of an http hook
+	Method    string   `yaml:"method,omitempty"`  // http method of an http hook (default POST)
+	Timeout   int      `yaml:"timeout,omitempty"` // timeout of the hook in seconds (0 = none)
+	OnFailure string   `yaml:"onFailure"`         // behaviour if the hook fails (abort/warn)
+}
+
+//------------------------------------------------------------------------------
+
+// NewHook creates a new hook
+func NewHook(name string, on string, hookType string) (*Hook, error) {
+	var hook Hook
+
+	hook.Name = name
+	hook.On = on
+	hook.Type = hookType
+	hook.Command = []string{}
+	hook.OnFailure = HookAbort
+
+	// success
+	return &hook, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Show displays the hook information as yaml
+func (hook *Hook) Show() (string, error) {
+	return util.ConvertToYAML(hook)
+}
+
+//------------------------------------------------------------------------------
+
+// Save writes the hook as yaml data to a file
+func (hook *Hook) Save(filename string) error {
+	return util.SaveYAML(filename, hook)
+}
+
+//------------------------------------------------------------------------------
+
+// Load reads the hook from a file
+func (hook *Hook) Load(filename string) error {
+	return util.LoadYAML(filename, hook)
+}
+
+//------------------------------------------------------------------------------
+
+// Validate checks the consistency of the hook.
+func (hook *Hook) Validate() error {
+	if hook.Name == "" {
+		return errors.New("hook requires a name")
+	}
+
+	if (hook.Phase() != HookPre && hook.Phase() != HookPost) || hook.Transition() == "" {
+		return errors.Errorf("hook '%s' requires a phase and a transition (e.g. pre-create)", hook.Name)
+	}
+
+	switch hook.Type {
+	case HookCommand:
+		if len(hook.Command) == 0 {
+			return errors.Errorf("hook '%s' requires a command", hook.Name)
+		}
+	case HookHTTP:
+		if hook.URL == "" {
+			return errors.Errorf("hook '%s' requires an url", hook.Name)
+		}
+	}
+
+	switch hook.OnFailure {
+	case "", HookAbort, HookWarn:
+	default:
+		return errors.Errorf("hook '%s' has an unknown failure behaviour: '%s'", hook.Name, hook.OnFailure)
+	}
+
+	if hook.Timeout < 0 {
+		return errors.Errorf("timeout of hook '%s' must not be negative", hook.Name)
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// Phase determines whether the hook is executed before or after the transition.
+func (hook *Hook) Phase() string {
+	return strings.SplitN(hook.On, "-", 2)[0]
+}
+
+//------------------------------------------------------------------------------
+
+// Transition determines the transition the hook is attached to.
+func (hook *Hook) Transition() string {
+	parts := strings.SplitN(hook.On, "-", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+//------------------------------------------------------------------------------
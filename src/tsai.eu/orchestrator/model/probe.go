@@ -0,0 +1,107 @@
+package model
+
+import (
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+// Probe
+// =====
+//
+// Attributes:
+//   - Type
+//   - Command
+//   - URL
+//   - Method
+//   - Interval
+//   - Deadline
+//   - Timeout
+//
+// Functions:
+//   - NewProbe
+//
+//   - probe.Show
+//   - probe.Load
+//   - probe.Save
+//
+//   - probe.Validate
+//------------------------------------------------------------------------------
+
+// ProbeController indicates that a probe is delegated to the controller of the component
+const ProbeController string = "controller"
+
+//------------------------------------------------------------------------------
+
+// Probe describes how the readiness or liveness of an instance is verified.
+type Probe struct {
+	Type     string   `yaml:"type"`              // type of the probe (command/http/controller)
+	Command  []string `yaml:"command,omitempty"` // command and arguments of a command probe
+	URL      string   `yaml:"url,omitempty"`     // url of an http probe
+	Method   string   `yaml:"method,omitempty"`  // http method of an http probe (default POST)
+	Interval int      `yaml:"interval"`          // interval between two checks in milliseconds
+	Deadline int      `yaml:"deadline"`          // time within which an instance needs to become ready in seconds (0 = none)
+	Timeout  int      `yaml:"timeout,omitempty"` // timeout of a single check in seconds (0 = none)
+}
+
+//------------------------------------------------------------------------------
+
+// NewProbe creates a new probe
+func NewProbe(probeType string) (*Probe, error) {
+	var probe Probe
+
+	probe.Type = probeType
+	probe.Command = []string{}
+	probe.Interval = 1000
+	probe.Deadline = 60
+
+	// success
+	return &probe, probe.Validate()
+}
+
+//------------------------------------------------------------------------------
+
+// Show displays the probe information as yaml
+func (probe *Probe) Show() (string, error) {
+	return util.ConvertToYAML(probe)
+}
+
+//------------------------------------------------------------------------------
+
+// Save writes the probe as yaml data to a file
+func (probe *Probe) Save(filename string) error {
+	return util.SaveYAML(filename, probe)
+}
+
+//------------------------------------------------------------------------------
+
+// Load reads the probe from a file
+func (probe *Probe) Load(filename string) error {
+	return util.LoadYAML(filename, probe)
+}
+
+//------------------------------------------------------------------------------
+
+// Validate checks the consistency of the probe.
+func (probe *Probe) Validate() error {
+	switch probe.Type {
+	case ProbeController:
+	case HookCommand:
+		if len(probe.Command) == 0 {
+			return errors.New("command probe requires a command")
+		}
+	case HookHTTP:
+		if probe.URL == "" {
+			return errors.New("http probe requires an url")
+		}
+	}
+
+	if probe.Interval < 0 || probe.Deadline < 0 || probe.Timeout < 0 {
+		return errors.New("negative values are not permitted")
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
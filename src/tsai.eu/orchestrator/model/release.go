@@ -0,0 +1,101 @@
+package model
+
+import (
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+// ReleasePolicy
+// =============
+//
+// Attributes:
+//   - Mode
+//   - Weight
+//
+// Functions:
+//   - NewReleasePolicy
+//
+//   - policy.Show
+//   - policy.Load
+//   - policy.Save
+//
+//   - policy.Validate
+//------------------------------------------------------------------------------
+
+// ReleaseBlueGreen brings up a new version completely before the endpoint of
+// the component is switched to it; the old version is kept for a rollback
+const ReleaseBlueGreen string = "bluegreen"
+
+// ReleaseCanary brings up a new version which receives a share of the traffic
+// until it is promoted or aborted
+const ReleaseCanary string = "canary"
+
+//------------------------------------------------------------------------------
+
+// ReleasePolicy describes how a new version of a service is released.
+type ReleasePolicy struct {
+	Mode   string `yaml:"mode"`   // release mode (bluegreen/canary)
+	Weight int    `yaml:"weight"` // share of the traffic of a canary release in percent
+}
+
+// WeightedEndpoint describes the share of the traffic of a version of a component.
+type WeightedEndpoint struct {
+	Version  string `yaml:"version"`  // version of the component
+	Endpoint string `yaml:"endpoint"` // endpoint of the version
+	Weight   int    `yaml:"weight"`   // share of the traffic in percent
+}
+
+//------------------------------------------------------------------------------
+
+// NewReleasePolicy creates a new release policy
+func NewReleasePolicy(mode string, weight int) (*ReleasePolicy, error) {
+	var policy ReleasePolicy
+
+	policy.Mode = mode
+	policy.Weight = weight
+
+	// success
+	return &policy, policy.Validate()
+}
+
+//------------------------------------------------------------------------------
+
+// Show displays the release policy information as yaml
+func (policy *ReleasePolicy) Show() (string, error) {
+	return util.ConvertToYAML(policy)
+}
+
+//------------------------------------------------------------------------------
+
+// Save writes the release policy as yaml data to a file
+func (policy *ReleasePolicy) Save(filename string) error {
+	return util.SaveYAML(filename, policy)
+}
+
+//------------------------------------------------------------------------------
+
+// Load reads the release policy from a file
+func (policy *ReleasePolicy) Load(filename string) error {
+	return util.LoadYAML(filename, policy)
+}
+
+//------------------------------------------------------------------------------
+
+// Validate checks the consistency of the release policy.
+func (policy *ReleasePolicy) Validate() error {
+	switch policy.Mode {
+	case ReleaseBlueGreen:
+	case ReleaseCanary:
+		if policy.Weight < 0 || policy.Weight > 100 {
+			return errors.New("weight of a canary release must be between 0 and 100")
+		}
+	default:
+		return errors.Errorf("unknown release mode: '%s'", policy.Mode)
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
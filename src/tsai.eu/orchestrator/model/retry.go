@@ -0,0 +1,147 @@
+package model
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+// RetryPolicy
+// ===========
+//
+// Attributes:
+//   - MaxAttempts
+//   - InitialDelay
+//   - MaxDelay
+//   - Multiplier
+//   - Jitter
+//   - Retryable
+//
+// Functions:
+//   - NewRetryPolicy
+//
+//   - policy.Show
+//   - policy.Load
+//   - policy.Save
+//
+//   - policy.Delay
+//   - policy.IsRetryable
+//------------------------------------------------------------------------------
+
+// RetryPolicy describes how often and when a failed operation is to be repeated.
+type RetryPolicy struct {
+	MaxAttempts  int      `yaml:"maxAttempts"`         // maximum number of attempts (including the first one)
+	InitialDelay int      `yaml:"initialDelay"`        // delay before the first retry in milliseconds
+	MaxDelay     int      `yaml:"maxDelay"`            // upper limit of a delay in milliseconds (0 = none)
+	Multiplier   float64  `yaml:"multiplier"`          // factor applied to the delay after each retry
+	Jitter       float64  `yaml:"jitter"`              // maximum random variation of a delay (0.0 - 1.0)
+	Retryable    []string `yaml:"retryable,omitempty"` // fragments of error messages which may be retried (empty = all)
+}
+
+//------------------------------------------------------------------------------
+
+// NewRetryPolicy creates a new retry policy
+func NewRetryPolicy(maxAttempts int, initialDelay int, multiplier float64) (*RetryPolicy, error) {
+	var policy RetryPolicy
+
+	policy.MaxAttempts = maxAttempts
+	policy.InitialDelay = initialDelay
+	policy.MaxDelay = 0
+	policy.Multiplier = multiplier
+	policy.Jitter = 0.0
+	policy.Retryable = []string{}
+
+	// success
+	return &policy, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Show displays the retry policy information as yaml
+func (policy *RetryPolicy) Show() (string, error) {
+	return util.ConvertToYAML(policy)
+}
+
+//------------------------------------------------------------------------------
+
+// Save writes the retry policy as yaml data to a file
+func (policy *RetryPolicy) Save(filename string) error {
+	return util.SaveYAML(filename, policy)
+}
+
+//------------------------------------------------------------------------------
+
+// Load reads the retry policy from a file
+func (policy *RetryPolicy) Load(filename string) error {
+	return util.LoadYAML(filename, policy)
+}
+
+//------------------------------------------------------------------------------
+
+// Delay determines the delay before the next attempt after a number of failed attempts.
+func (policy *RetryPolicy) Delay(attempts int) time.Duration {
+	// exponential backoff
+	multiplier := policy.Multiplier
+	if multiplier < 1.0 {
+		multiplier = 1.0
+	}
+
+	delay := float64(policy.InitialDelay) * math.Pow(multiplier, float64(attempts-1))
+
+	// limit delay
+	if policy.MaxDelay > 0 && delay > float64(policy.MaxDelay) {
+		delay = float64(policy.MaxDelay)
+	}
+
+	// add random variation
+	if policy.Jitter > 0.0 {
+		delay = delay * (1.0 + policy.Jitter*(2.0*rand.Float64()-1.0))
+	}
+
+	if delay < 0.0 {
+		delay = 0.0
+	}
+
+	// success
+	return time.Duration(delay) * time.Millisecond
+}
+
+//------------------------------------------------------------------------------
+
+// temporary is implemented by errors which know if they are of a temporary nature.
+type temporary interface {
+	Temporary() bool
+}
+
+// IsRetryable determines if an operation which has failed with an error may be retried.
+func (policy *RetryPolicy) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// errors may classify themselves
+	if t, ok := err.(temporary); ok {
+		return t.Temporary()
+	}
+
+	// all errors are retryable if no classification has been defined
+	if len(policy.Retryable) == 0 {
+		return true
+	}
+
+	// check if the error message matches a retryable error
+	for _, fragment := range policy.Retryable {
+		if strings.Contains(err.Error(), fragment) {
+			return true
+		}
+	}
+
+	// error is permanent
+	return false
+}
+
+//------------------------------------------------------------------------------
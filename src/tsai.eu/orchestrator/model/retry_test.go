@@ -0,0 +1,97 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+
+func TestRetryPolicyDelay(t *testing.T) {
+	tests := []struct {
+		name         string
+		initialDelay int
+		maxDelay     int
+		multiplier   float64
+		attempts     int
+		expected     time.Duration
+	}{
+		{"first retry", 100, 0, 2.0, 1, 100 * time.Millisecond},
+		{"second retry", 100, 0, 2.0, 2, 200 * time.Millisecond},
+		{"fourth retry", 100, 0, 2.0, 4, 800 * time.Millisecond},
+		{"constant", 100, 0, 1.0, 5, 100 * time.Millisecond},
+		{"multiplier below one", 100, 0, 0.5, 3, 100 * time.Millisecond},
+		{"limited", 100, 500, 2.0, 4, 500 * time.Millisecond},
+		{"below limit", 100, 500, 2.0, 2, 200 * time.Millisecond},
+		{"no delay", 0, 0, 2.0, 3, 0},
+		{"negative delay", -100, 0, 2.0, 1, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy, _ := NewRetryPolicy(5, test.initialDelay, test.multiplier)
+			policy.MaxDelay = test.maxDelay
+
+			if delay := policy.Delay(test.attempts); delay != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, delay)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestRetryPolicyJitter(t *testing.T) {
+	policy, _ := NewRetryPolicy(5, 1000, 1.0)
+	policy.Jitter = 0.2
+
+	for i := 0; i < 100; i++ {
+		delay := policy.Delay(1)
+		if delay < 800*time.Millisecond || delay > 1200*time.Millisecond {
+			t.Fatalf("delay %v exceeds the jitter", delay)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// temporaryError is an error which classifies itself.
+type temporaryError struct {
+	temporary bool
+}
+
+func (err temporaryError) Error() string   { return "connection refused" }
+func (err temporaryError) Temporary() bool { return err.temporary }
+
+//------------------------------------------------------------------------------
+
+func TestRetryPolicyIsRetryable(t *testing.T) {
+	tests := []struct {
+		name      string
+		retryable []string
+		err       error
+		expected  bool
+	}{
+		{"no error", []string{}, nil, false},
+		{"unclassified", []string{}, errors.New("disk full"), true},
+		{"matching fragment", []string{"timeout", "refused"}, errors.New("connection refused"), true},
+		{"no matching fragment", []string{"timeout"}, errors.New("disk full"), false},
+		{"temporary", []string{"timeout"}, temporaryError{temporary: true}, true},
+		{"permanent", []string{}, temporaryError{temporary: false}, false},
+		{"permanent despite fragment", []string{"refused"}, temporaryError{temporary: false}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy, _ := NewRetryPolicy(5, 100, 2.0)
+			policy.Retryable = test.retryable
+
+			if retryable := policy.IsRetryable(test.err); retryable != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, retryable)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,188 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+// Schedule
+// ========
+//
+// Attributes:
+//   - UUID
+//   - Architecture
+//   - Expression
+//   - Policy
+//   - Next
+//   - Pending
+//   - History
+//
+// Functions:
+//   - NewSchedule
+//
+//   - schedule.Show
+//   - schedule.Load
+//   - schedule.Save
+//
+//   - schedule.Validate
+//   - schedule.Advance
+//   - schedule.AddRun
+//   - schedule.LastRun
+//------------------------------------------------------------------------------
+
+// SchedulePolicySkip indicates that a run is skipped if the previous run is still executing
+const SchedulePolicySkip string = "skip"
+
+// SchedulePolicyQueue indicates that a run is started once the previous run has finished
+const SchedulePolicyQueue string = "queue"
+
+// ScheduleHistoryLimit defines the number of runs kept in the history of a schedule
+const ScheduleHistoryLimit int = 100
+
+//------------------------------------------------------------------------------
+
+// ScheduledRunStarted indicates that a scheduled run has been started
+const ScheduledRunStarted string = "started"
+
+// ScheduledRunSkipped indicates that a scheduled run has been skipped
+const ScheduledRunSkipped string = "skipped"
+
+// ScheduledRunQueued indicates that a scheduled run waits for the previous run
+const ScheduledRunQueued string = "queued"
+
+// ScheduledRunFailed indicates that a scheduled run could not be started
+const ScheduledRunFailed string = "failed"
+
+//------------------------------------------------------------------------------
+
+// Schedule triggers the execution of an architecture at the times defined by a
+// cron expression.
+type Schedule struct {
+	UUID         string          `yaml:"uuid"`         // uuid of the schedule
+	Architecture string          `yaml:"architecture"` // name of the architecture to be executed
+	Expression   string          `yaml:"expression"`   // cron expression (minute hour day month weekday)
+	Policy       string          `yaml:"policy"`       // behaviour if the previous run is still executing (skip/queue)
+	Next         int64           `yaml:"next"`         // time of the next run (nsecs since 1.1.1970)
+	Pending      bool            `yaml:"pending"`      // a queued run waits for the previous run
+	History      []*ScheduledRun `yaml:"history"`      // latest runs of the schedule
+}
+
+// ScheduledRun records a single run of a schedule.
+type ScheduledRun struct {
+	Time   int64  `yaml:"time"`   // time of the run (nsecs since 1.1.1970)
+	Status string `yaml:"status"` // outcome of the run (started/skipped/queued/failed)
+	Task   string `yaml:"task"`   // uuid of the resulting architecture task
+	Error  string `yaml:"error"`  // reason why the run could not be started
+}
+
+//------------------------------------------------------------------------------
+
+// NewSchedule creates a new schedule
+func NewSchedule(architecture string, expression string) (*Schedule, error) {
+	var schedule Schedule
+
+	schedule.UUID = uuid.New().String()
+	schedule.Architecture = architecture
+	schedule.Expression = expression
+	schedule.Policy = SchedulePolicySkip
+	schedule.Pending = false
+	schedule.History = []*ScheduledRun{}
+
+	err := schedule.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Advance(time.Now())
+
+	// success
+	return &schedule, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Show displays the schedule information as yaml
+func (schedule *Schedule) Show() (string, error) {
+	return util.ConvertToYAML(schedule)
+}
+
+//------------------------------------------------------------------------------
+
+// Save writes the schedule as yaml data to a file
+func (schedule *Schedule) Save(filename string) error {
+	return util.SaveYAML(filename, schedule)
+}
+
+//------------------------------------------------------------------------------
+
+// Load reads the schedule from a file
+func (schedule *Schedule) Load(filename string) error {
+	return util.LoadYAML(filename, schedule)
+}
+
+//------------------------------------------------------------------------------
+
+// Validate checks the consistency of the schedule.
+func (schedule *Schedule) Validate() error {
+	if schedule.Architecture == "" {
+		return errors.New("schedule requires an architecture")
+	}
+
+	if _, err := util.ParseCron(schedule.Expression); err != nil {
+		return err
+	}
+
+	switch schedule.Policy {
+	case SchedulePolicySkip, SchedulePolicyQueue:
+	default:
+		return errors.Errorf("unknown schedule policy: '%s'", schedule.Policy)
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// Advance determines the time of the next run after a point in time.
+func (schedule *Schedule) Advance(t time.Time) {
+	schedule.Next = 0
+
+	cron, err := util.ParseCron(schedule.Expression)
+	if err != nil {
+		return
+	}
+
+	if next := cron.Next(t); !next.IsZero() {
+		schedule.Next = next.UnixNano()
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// AddRun records a run in the history of the schedule.
+func (schedule *Schedule) AddRun(run *ScheduledRun) {
+	schedule.History = append(schedule.History, run)
+
+	if len(schedule.History) > ScheduleHistoryLimit {
+		schedule.History = schedule.History[len(schedule.History)-ScheduleHistoryLimit:]
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// LastRun provides the latest run of the schedule which has been started.
+func (schedule *Schedule) LastRun() *ScheduledRun {
+	for index := len(schedule.History) - 1; index >= 0; index-- {
+		if schedule.History[index].Status == ScheduledRunStarted {
+			return schedule.History[index]
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,342 @@
+package model
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+// StateMachine
+// ============
+//
+// Attributes:
+//   - Name
+//   - Initial
+//   - Failure
+//   - Running
+//   - States
+//   - Transitions
+//
+// Functions:
+//   - NewStateMachine
+//   - DefaultStateMachine
+//   - RegisterStateMachine
+//   - GetStateMachine
+//   - IsValidComponentState
+//
+//   - machine.Show
+//   - machine.Load
+//   - machine.Save
+//
+//   - machine.Validate
+//   - machine.AddState
+//   - machine.AddTransition
+//   - machine.IsValidState
+//   - machine.GetTransition
+//   - machine.GetTransitionResult
+//   - machine.GetTransitions
+//------------------------------------------------------------------------------
+
+// StateTransition describes a transition between two states of a state machine.
+type StateTransition struct {
+	Name   string `yaml:"name"`           // name of the transition
+	From   string `yaml:"from"`           // state in which the transition can be executed
+	To     string `yaml:"to"`             // state resulting from the transition
+	Method string `yaml:"method"`         // controller method implementing the transition
+	Then   string `yaml:"then,omitempty"` // transition without change of state to be executed directly afterwards (optional)
+}
+
+// StateMachine describes the lifecycle of the instances of a component type.
+type StateMachine struct {
+	Name        string             `yaml:"name"`        // name of the state machine
+	Initial     string             `yaml:"initial"`     // state of an instance which does not exist
+	Failure     string             `yaml:"failure"`     // state of an instance which has failed
+	Running     string             `yaml:"running"`     // state of an instance which is operational ("" = none)
+	States      []string           `yaml:"states"`      // list of states
+	Transitions []*StateTransition `yaml:"transitions"` // list of transitions
+}
+
+//------------------------------------------------------------------------------
+
+// stateMachines is the registry of state machines per component type
+var stateMachines = map[string]*StateMachine{}
+var stateMachinesLock sync.RWMutex
+
+// defaultStateMachine is the built-in lifecycle of instances
+var defaultStateMachine *StateMachine
+var defaultStateMachineInit sync.Once
+
+//------------------------------------------------------------------------------
+
+// NewStateMachine creates a new state machine
+func NewStateMachine(name string, initial string, failure string) (*StateMachine, error) {
+	var machine StateMachine
+
+	machine.Name = name
+	machine.Initial = initial
+	machine.Failure = failure
+	machine.States = []string{}
+	machine.Transitions = []*StateTransition{}
+
+	machine.AddState(initial)
+	machine.AddState(failure)
+
+	// success
+	return &machine, nil
+}
+
+//------------------------------------------------------------------------------
+
+// DefaultStateMachine provides the built-in lifecycle which applies to all
+// component types without a state machine of their own.
+func DefaultStateMachine() *StateMachine {
+	// initialise singleton once
+	defaultStateMachineInit.Do(func() {
+		defaultStateMachine, _ = NewStateMachine("default", InitialState, FailureState)
+
+		defaultStateMachine.AddState(InactiveState)
+		defaultStateMachine.AddState(ActiveState)
+
+		defaultStateMachine.Running = ActiveState
+
+		defaultStateMachine.AddTransition("create", InitialState, InactiveState, "create", "configure")
+		defaultStateMachine.AddTransition("configure", InactiveState, InactiveState, "configure", "")
+		defaultStateMachine.AddTransition("start", InactiveState, ActiveState, "start", "")
+		defaultStateMachine.AddTransition("destroy", InactiveState, InitialState, "destroy", "")
+		defaultStateMachine.AddTransition("configure", ActiveState, ActiveState, "configure", "")
+		defaultStateMachine.AddTransition("stop", ActiveState, InactiveState, "stop", "")
+		defaultStateMachine.AddTransition("reset", FailureState, InitialState, "reset", "")
+	})
+
+	return defaultStateMachine
+}
+
+//------------------------------------------------------------------------------
+
+// RegisterStateMachine defines the state machine of a component type.
+func RegisterStateMachine(componentType string, machine *StateMachine) error {
+	if machine == nil {
+		return errors.New("undefined state machine")
+	}
+
+	// check consistency of state machine
+	if err := machine.Validate(); err != nil {
+		return err
+	}
+
+	// the method of a transition defaults to its name
+	for _, transition := range machine.Transitions {
+		if transition.Method == "" {
+			transition.Method = transition.Name
+		}
+	}
+
+	stateMachinesLock.Lock()
+	stateMachines[componentType] = machine
+	stateMachinesLock.Unlock()
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// GetStateMachine determines the state machine of a component type.
+func GetStateMachine(componentType string) *StateMachine {
+	stateMachinesLock.RLock()
+	machine, found := stateMachines[componentType]
+	stateMachinesLock.RUnlock()
+
+	// fall back to the built-in lifecycle
+	if !found {
+		return DefaultStateMachine()
+	}
+
+	// success
+	return machine
+}
+
+//------------------------------------------------------------------------------
+
+// IsValidComponentState determines if a string resembles a valid state of a component type.
+func IsValidComponentState(componentType string, state string) bool {
+	return GetStateMachine(componentType).IsValidState(state)
+}
+
+//------------------------------------------------------------------------------
+
+// Show displays the state machine information as yaml
+func (machine *StateMachine) Show() (string, error) {
+	return util.ConvertToYAML(machine)
+}
+
+//------------------------------------------------------------------------------
+
+// Save writes the state machine as yaml data to a file
+func (machine *StateMachine) Save(filename string) error {
+	return util.SaveYAML(filename, machine)
+}
+
+//------------------------------------------------------------------------------
+
+// Load reads the state machine from a file
+func (machine *StateMachine) Load(filename string) error {
+	return util.LoadYAML(filename, machine)
+}
+
+//------------------------------------------------------------------------------
+
+// Validate checks the consistency of the state machine.
+func (machine *StateMachine) Validate() error {
+	if !machine.IsValidState(machine.Initial) {
+		return errors.New("invalid initial state")
+	}
+	if !machine.IsValidState(machine.Failure) {
+		return errors.New("invalid failure state")
+	}
+	if machine.Running != "" && !machine.IsValidState(machine.Running) {
+		return errors.New("invalid running state")
+	}
+
+	for _, transition := range machine.Transitions {
+		if transition.Name == "" {
+			return errors.New("transition without name")
+		}
+		if !machine.IsValidState(transition.From) || !machine.IsValidState(transition.To) {
+			return errors.Errorf("transition '%s' refers to an invalid state", transition.Name)
+		}
+		if transition.Then != "" {
+			then, err := machine.GetTransition(transition.To, transition.Then)
+			if err != nil || then.To != transition.To {
+				return errors.Errorf("transition '%s' is followed by an invalid transition", transition.Name)
+			}
+		}
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// AddState adds a state to the state machine.
+func (machine *StateMachine) AddState(state string) {
+	if !machine.IsValidState(state) {
+		machine.States = append(machine.States, state)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// AddTransition adds a transition to the state machine. The method defaults to the name of the transition.
+func (machine *StateMachine) AddTransition(name string, from string, to string, method string, then string) {
+	if method == "" {
+		method = name
+	}
+
+	machine.Transitions = append(machine.Transitions, &StateTransition{
+		Name:   name,
+		From:   from,
+		To:     to,
+		Method: method,
+		Then:   then,
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// IsValidState determines if a string resembles a state of the state machine.
+func (machine *StateMachine) IsValidState(state string) bool {
+	for _, s := range machine.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+//------------------------------------------------------------------------------
+
+// GetTransition determines a transition which can be executed in a current state.
+func (machine *StateMachine) GetTransition(currentState string, name string) (*StateTransition, error) {
+	if !machine.IsValidState(currentState) {
+		return nil, errors.New("invalid state")
+	}
+
+	for _, transition := range machine.Transitions {
+		if transition.From == currentState && transition.Name == name {
+			return transition, nil
+		}
+	}
+
+	// transition is not possible
+	return nil, errors.New("invalid transition")
+}
+
+//------------------------------------------------------------------------------
+
+// GetTransitionResult determines the state resulting from a transition applied to a current state.
+func (machine *StateMachine) GetTransitionResult(currentState string, name string) (string, error) {
+	transition, err := machine.GetTransition(currentState, name)
+	if err != nil {
+		return "", err
+	}
+
+	// success
+	return transition.To, nil
+}
+
+//------------------------------------------------------------------------------
+
+// GetTransitions determines the sequence of transitions required to move from
+// a current state to a target state. Transitions which are to be followed by
+// another transition are complemented accordingly.
+func (machine *StateMachine) GetTransitions(currentState string, targetState string) ([]string, error) {
+	// check parameters
+	if !machine.IsValidState(currentState) || !machine.IsValidState(targetState) {
+		return nil, errors.New("invalid state")
+	}
+
+	// determine the shortest path with a breadth first search
+	paths := map[string][]*StateTransition{currentState: {}}
+	queue := []string{currentState}
+
+	for len(queue) > 0 && paths[targetState] == nil {
+		state := queue[0]
+		queue = queue[1:]
+
+		// visit all states which can be reached
+		for _, transition := range machine.Transitions {
+			if transition.From != state {
+				continue
+			}
+			if _, visited := paths[transition.To]; visited {
+				continue
+			}
+
+			paths[transition.To] = append(append([]*StateTransition{}, paths[state]...), transition)
+			queue = append(queue, transition.To)
+		}
+	}
+
+	path, ok := paths[targetState]
+	if !ok {
+		return nil, errors.New("invalid transition")
+	}
+
+	// add subsequent transitions
+	transitions := []string{}
+	for _, transition := range path {
+		transitions = append(transitions, transition.Name)
+		if transition.Then != "" {
+			transitions = append(transitions, transition.Then)
+		}
+	}
+
+	//success
+	return transitions, nil
+}
+
+//------------------------------------------------------------------------------
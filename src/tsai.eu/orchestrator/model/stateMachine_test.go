@@ -0,0 +1,155 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+//------------------------------------------------------------------------------
+
+func TestStateMachineGetTransitions(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		target   string
+		expected []string
+		fails    bool
+	}{
+		{"unchanged", ActiveState, ActiveState, []string{}, false},
+		{"create", InitialState, InactiveState, []string{"create", "configure"}, false},
+		{"create and start", InitialState, ActiveState, []string{"create", "configure", "start"}, false},
+		{"start", InactiveState, ActiveState, []string{"start"}, false},
+		{"stop", ActiveState, InactiveState, []string{"stop"}, false},
+		{"stop and destroy", ActiveState, InitialState, []string{"stop", "destroy"}, false},
+		{"reset", FailureState, InitialState, []string{"reset"}, false},
+		{"recover", FailureState, ActiveState, []string{"reset", "create", "configure", "start"}, false},
+		{"unreachable", ActiveState, FailureState, nil, true},
+		{"invalid current state", "unknown", ActiveState, nil, true},
+		{"invalid target state", InitialState, "unknown", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transitions, err := DefaultStateMachine().GetTransitions(test.current, test.target)
+			if test.fails {
+				if err == nil {
+					t.Errorf("expected an error, got %v", transitions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(transitions, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, transitions)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestStateMachineGetTransitionsShortestPath(t *testing.T) {
+	machine, _ := NewStateMachine("shortcut", "initial", "failure")
+	machine.AddState("a")
+	machine.AddState("b")
+	machine.AddState("c")
+	machine.AddTransition("first", "initial", "a", "", "")
+	machine.AddTransition("second", "a", "b", "", "")
+	machine.AddTransition("third", "b", "c", "", "")
+	machine.AddTransition("direct", "a", "c", "", "")
+
+	transitions, err := machine.GetTransitions("initial", "c")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"first", "direct"}
+	if !reflect.DeepEqual(transitions, expected) {
+		t.Errorf("expected %v, got %v", expected, transitions)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestRegisterStateMachine(t *testing.T) {
+	machine, _ := NewStateMachine("valid", "initial", "failure")
+	machine.AddState("running")
+	machine.Running = "running"
+	machine.Transitions = append(machine.Transitions,
+		&StateTransition{Name: "create", From: "initial", To: "running", Then: "configure"},
+		&StateTransition{Name: "configure", From: "running", To: "running"})
+
+	if err := RegisterStateMachine("register-valid", machine); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if GetStateMachine("register-valid") != machine {
+		t.Errorf("expected the registered state machine")
+	}
+	for _, transition := range machine.Transitions {
+		if transition.Method != transition.Name {
+			t.Errorf("expected method %s, got %s", transition.Name, transition.Method)
+		}
+	}
+	if !IsValidComponentState("register-valid", "running") || IsValidComponentState("register-valid", ActiveState) {
+		t.Errorf("expected the states of the registered state machine")
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestRegisterInvalidStateMachine(t *testing.T) {
+	tests := []struct {
+		name        string
+		initial     string
+		failure     string
+		running     string
+		transitions []StateTransition
+	}{
+		{name: "invalid initial state", initial: "unknown"},
+		{name: "invalid failure state", failure: "unknown"},
+		{name: "invalid running state", running: "unknown"},
+		{name: "transition without name", transitions: []StateTransition{{Name: "", From: "initial", To: "failure"}}},
+		{name: "transition to an invalid state", transitions: []StateTransition{{Name: "create", From: "initial", To: "unknown"}}},
+		{name: "invalid subsequent transition", transitions: []StateTransition{{Name: "create", From: "initial", To: "running", Then: "configure"}}},
+		{name: "subsequent transition changing the state", transitions: []StateTransition{
+			{Name: "create", From: "initial", To: "running", Then: "destroy"},
+			{Name: "destroy", From: "running", To: "initial"},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			componentType := "register-" + test.name
+
+			machine, _ := NewStateMachine("invalid", "initial", "failure")
+			machine.AddState("running")
+			if test.initial != "" {
+				machine.Initial = test.initial
+			}
+			if test.failure != "" {
+				machine.Failure = test.failure
+			}
+			if test.running != "" {
+				machine.Running = test.running
+			}
+			for _, transition := range test.transitions {
+				machine.AddTransition(transition.Name, transition.From, transition.To, "", transition.Then)
+			}
+
+			if err := RegisterStateMachine(componentType, machine); err == nil {
+				t.Errorf("expected an error")
+			}
+			if GetStateMachine(componentType) != DefaultStateMachine() {
+				t.Errorf("expected the default state machine for an unregistered type")
+			}
+		})
+	}
+
+	if err := RegisterStateMachine("register-undefined", nil); err == nil {
+		t.Errorf("expected an error for an undefined state machine")
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -1,6 +1,9 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
 //------------------------------------------------------------------------------
 
@@ -17,6 +20,10 @@ type ComponentStatus struct {
 	Changed           bool   `yaml:"Changed"`           // indicator if a change occured
 }
 
+// statusLock protects the endpoints and states which are updated by SetStatus
+// while the configurations of concurrently running tasks are compiled.
+var statusLock sync.RWMutex
+
 //------------------------------------------------------------------------------
 
 // DeriveComponentStatus derives a ComponentStatus from a ComponentConfiguration struct
@@ -48,13 +55,18 @@ func DeriveComponentStatus(configuration *ComponentConfiguration) (status *Compo
 // SetStatus saves the status received from a controller.
 func SetStatus(status ComponentStatus) (err error) {
 	if status.Changed {
+		statusLock.Lock()
+		defer statusLock.Unlock()
+
 		// TODO: proper error handling
 		domain, _ := GetModel().GetDomain(status.Domain)
 		component, _ := domain.GetComponent(status.Component)
 		instance, _ := component.GetInstance(status.Instance)
 
-		// update component
-		component.Endpoint = status.ComponentEndpoint
+		// update component (the endpoint of a released component refers to its active version)
+		if component.Active == "" || component.Active == instance.Version {
+			component.Endpoint = status.ComponentEndpoint
+		}
 		component.AddEndpoint(instance.Version, status.VersionEndpoint)
 
 		// update instance
@@ -65,3 +77,16 @@ func SetStatus(status ComponentStatus) (err error) {
 	// success
 	return nil
 }
+
+//------------------------------------------------------------------------------
+
+// SetInstanceState saves the state of an instance reported by a controller
+// which has not changed any endpoints.
+func SetInstanceState(instance *Instance, state string) {
+	statusLock.Lock()
+	defer statusLock.Unlock()
+
+	instance.State = state
+}
+
+//------------------------------------------------------------------------------
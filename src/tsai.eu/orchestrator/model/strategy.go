@@ -0,0 +1,116 @@
+package model
+
+import (
+	"github.com/pkg/errors"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+// UpdateStrategy
+// ==============
+//
+// Attributes:
+//   - Type
+//   - MaxUnavailable
+//   - MaxSurge
+//   - BatchSize
+//   - Pause
+//
+// Functions:
+//   - NewUpdateStrategy
+//
+//   - strategy.Show
+//   - strategy.Load
+//   - strategy.Save
+//
+//   - strategy.Validate
+//   - strategy.Batch
+//------------------------------------------------------------------------------
+
+// StrategyRecreate removes all old instances before new instances are created
+const StrategyRecreate string = "recreate"
+
+// StrategyRolling replaces old instances by new instances in batches
+const StrategyRolling string = "rolling"
+
+//------------------------------------------------------------------------------
+
+// UpdateStrategy describes how the instances of a service are replaced.
+type UpdateStrategy struct {
+	Type           string `yaml:"type"`           // type of the strategy (recreate/rolling)
+	MaxUnavailable int    `yaml:"maxUnavailable"` // number of instances which may be removed before their replacements exist
+	MaxSurge       int    `yaml:"maxSurge"`       // number of instances which may be created before old instances are removed
+	BatchSize      int    `yaml:"batchSize"`      // number of instances replaced per batch (0 = maxSurge + maxUnavailable)
+	Pause          int    `yaml:"pause"`          // pause between two batches in milliseconds
+}
+
+//------------------------------------------------------------------------------
+
+// NewUpdateStrategy creates a new update strategy
+func NewUpdateStrategy(strategyType string) (*UpdateStrategy, error) {
+	var strategy UpdateStrategy
+
+	strategy.Type = strategyType
+	strategy.MaxUnavailable = 0
+	strategy.MaxSurge = 1
+	strategy.BatchSize = 0
+	strategy.Pause = 0
+
+	// success
+	return &strategy, strategy.Validate()
+}
+
+//------------------------------------------------------------------------------
+
+// Show displays the update strategy information as yaml
+func (strategy *UpdateStrategy) Show() (string, error) {
+	return util.ConvertToYAML(strategy)
+}
+
+//------------------------------------------------------------------------------
+
+// Save writes the update strategy as yaml data to a file
+func (strategy *UpdateStrategy) Save(filename string) error {
+	return util.SaveYAML(filename, strategy)
+}
+
+//------------------------------------------------------------------------------
+
+// Load reads the update strategy from a file
+func (strategy *UpdateStrategy) Load(filename string) error {
+	return util.LoadYAML(filename, strategy)
+}
+
+//------------------------------------------------------------------------------
+
+// Validate checks the consistency of the update strategy.
+func (strategy *UpdateStrategy) Validate() error {
+	switch strategy.Type {
+	case StrategyRecreate:
+	case StrategyRolling:
+		if strategy.MaxUnavailable+strategy.MaxSurge <= 0 {
+			return errors.New("rolling update requires maxUnavailable or maxSurge")
+		}
+	default:
+		return errors.Errorf("unknown update strategy: '%s'", strategy.Type)
+	}
+
+	if strategy.MaxUnavailable < 0 || strategy.MaxSurge < 0 || strategy.BatchSize < 0 || strategy.Pause < 0 {
+		return errors.New("negative values are not permitted")
+	}
+
+	// success
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// Batch determines the number of instances replaced per batch of a rolling update.
+func (strategy *UpdateStrategy) Batch() int {
+	if strategy.BatchSize > 0 {
+		return strategy.BatchSize
+	}
+	return strategy.MaxUnavailable + strategy.MaxSurge
+}
+
+//------------------------------------------------------------------------------
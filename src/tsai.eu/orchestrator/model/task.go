@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"sync"
 
 	"tsai.eu/orchestrator/util"
 )
@@ -27,6 +28,31 @@ const (
 	TaskStatusTerminated
 )
 
+// TaskStatus2String converts a TaskStatus to a string
+func TaskStatus2String(status TaskStatus) (string, error) {
+	switch status {
+	case TaskStatusInitial:
+		return "initial", nil
+	case TaskStatusExecuting:
+		return "executing", nil
+	case TaskStatusCompleted:
+		return "completed", nil
+	case TaskStatusFailed:
+		return "failed", nil
+	case TaskStatusTimeout:
+		return "timeout", nil
+	case TaskStatusTerminated:
+		return "terminated", nil
+	}
+	return "", errors.New("unknown status")
+}
+
+//------------------------------------------------------------------------------
+
+// taskLock protects the fields of tasks which are accessed concurrently by the
+// dispatcher, the workers and the shell.
+var taskLock sync.RWMutex
+
 //------------------------------------------------------------------------------
 
 // TaskHandler is function capable of processing a task related event.
@@ -43,11 +69,23 @@ type Task struct {
 	Version      string     `yaml:"version"`      // version of entity
 	Instance     string     `yaml:"instance"`     // instance of entity
 	State        string     `yaml:"state"`        // desired state of entity
+	Transition   string     `yaml:"transition"`   // transition to be executed
 	UUID         string     `yaml:"uuid"`         // uuid of task
 	Parent       string     `yaml:"parent"`       // uuid of parent task
 	Status       TaskStatus `yaml:"status"`       // status of task: (execution/completion/failure)
 	Phase        int        `yaml:"phase"`        // phase of task
 	Subtasks     []string   `yaml:"subtasks"`     // list of subtasks
+	Started      int64      `yaml:"started"`      // start of the execution (nsecs since 1.1.1970)
+	Finished     int64      `yaml:"finished"`     // end of the execution (nsecs since 1.1.1970, 0 = not finished)
+	Deadline     int64      `yaml:"deadline"`     // deadline of the execution (nsecs since 1.1.1970, 0 = none)
+	Attempts     int        `yaml:"attempts"`     // number of attempts to execute the task
+	Error        string     `yaml:"error"`        // error message of the last failed attempt
+	Delay        int        `yaml:"delay"`        // duration of a pause in milliseconds
+	Rollback     string     `yaml:"rollback"`     // uuid of the task compensating this task
+	Compensates  string     `yaml:"compensates"`  // uuid of the failed task compensated by this task
+	Gate         string     `yaml:"gate"`         // name of the approval gate
+	Hook         string     `yaml:"hook"`         // name of the hook of the template variant
+	Paused       bool       `yaml:"paused"`       // subtasks are not started while paused
 	execute      TaskHandler
 	terminate    TaskHandler
 	failed       TaskHandler
@@ -106,6 +144,13 @@ func (task *Task) GetState() string {
 
 //------------------------------------------------------------------------------
 
+// GetTransition delivers the transition to be executed.
+func (task *Task) GetTransition() string {
+	return task.Transition
+}
+
+//------------------------------------------------------------------------------
+
 // GetUUID delivers the universal unique identifier of the task.
 func (task *Task) GetUUID() string {
 	return task.UUID
@@ -122,11 +167,23 @@ func (task *Task) GetParent() string {
 
 // GetStatus delivers the status of the task.
 func (task *Task) GetStatus() TaskStatus {
+	taskLock.RLock()
+	defer taskLock.RUnlock()
+
 	return task.Status
 }
 
 //------------------------------------------------------------------------------
 
+// SetStatus updates the status of the task.
+func (task *Task) SetStatus(status TaskStatus) {
+	taskLock.Lock()
+	task.Status = status
+	taskLock.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
 // GetPhase delivers the internal status of the task.
 func (task *Task) GetPhase() int {
 	return task.Phase
@@ -134,6 +191,130 @@ func (task *Task) GetPhase() int {
 
 //------------------------------------------------------------------------------
 
+// GetStarted delivers the start time of the execution of the task.
+func (task *Task) GetStarted() int64 {
+	taskLock.RLock()
+	defer taskLock.RUnlock()
+
+	return task.Started
+}
+
+//------------------------------------------------------------------------------
+
+// SetStarted records the start time of the execution of the task.
+func (task *Task) SetStarted(started int64) {
+	taskLock.Lock()
+	task.Started = started
+	taskLock.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
+// GetFinished delivers the end time of the execution of the task.
+func (task *Task) GetFinished() int64 {
+	taskLock.RLock()
+	defer taskLock.RUnlock()
+
+	return task.Finished
+}
+
+//------------------------------------------------------------------------------
+
+// SetFinished records the end time of the execution of the task.
+func (task *Task) SetFinished(finished int64) {
+	taskLock.Lock()
+	task.Finished = finished
+	taskLock.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
+// GetDeadline delivers the time by which the task needs to be finished.
+func (task *Task) GetDeadline() int64 {
+	taskLock.RLock()
+	defer taskLock.RUnlock()
+
+	return task.Deadline
+}
+
+//------------------------------------------------------------------------------
+
+// SetDeadline defines the time by which the task needs to be finished.
+func (task *Task) SetDeadline(deadline int64) {
+	taskLock.Lock()
+	task.Deadline = deadline
+	taskLock.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
+// GetAttempts delivers the number of attempts to execute the task.
+func (task *Task) GetAttempts() int {
+	return task.Attempts
+}
+
+//------------------------------------------------------------------------------
+
+// GetDelay delivers the duration of a pause in milliseconds.
+func (task *Task) GetDelay() int {
+	return task.Delay
+}
+
+//------------------------------------------------------------------------------
+
+// GetError delivers the error message of the last failed attempt.
+func (task *Task) GetError() string {
+	taskLock.RLock()
+	defer taskLock.RUnlock()
+
+	return task.Error
+}
+
+//------------------------------------------------------------------------------
+
+// SetError records the error message of a failed attempt.
+func (task *Task) SetError(err error) {
+	taskLock.Lock()
+	defer taskLock.Unlock()
+
+	if err != nil {
+		task.Error = err.Error()
+	} else {
+		task.Error = ""
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// SetRollback records the task which compensates the task.
+func (task *Task) SetRollback(uuid string) {
+	taskLock.Lock()
+	defer taskLock.Unlock()
+
+	task.Rollback = uuid
+}
+
+//------------------------------------------------------------------------------
+
+// IsPaused checks if the subtasks of the task are held back.
+func (task *Task) IsPaused() bool {
+	taskLock.RLock()
+	defer taskLock.RUnlock()
+
+	return task.Paused
+}
+
+//------------------------------------------------------------------------------
+
+// SetPaused defines if the subtasks of the task are held back.
+func (task *Task) SetPaused(paused bool) {
+	taskLock.Lock()
+	task.Paused = paused
+	taskLock.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
 // GetSubtask provides the subtask with a given uuid.
 func (task *Task) GetSubtask(uuid string) (*Task, error) {
 	// check if uuid is in slice of substasks
@@ -173,7 +354,23 @@ func (task *Task) GetSubtasks() []string {
 
 // AddSubtask adds a subtask to the list of subtasks.
 func (task *Task) AddSubtask(subtask *Task) {
+	taskLock.Lock()
 	task.Subtasks = append(task.Subtasks, subtask.GetUUID())
+	taskLock.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
+// Snapshot provides a copy of the task which is not affected by subsequent
+// changes of the task.
+func (task *Task) Snapshot() *Task {
+	taskLock.RLock()
+	defer taskLock.RUnlock()
+
+	snapshot := *task
+	snapshot.Subtasks = append([]string{}, task.Subtasks...)
+
+	return &snapshot
 }
 
 //------------------------------------------------------------------------------
@@ -185,6 +382,13 @@ func (task *Task) Save(filename string) error {
 
 //------------------------------------------------------------------------------
 
+// Load reads the task from a file
+func (task *Task) Load(filename string) error {
+	return util.LoadYAML(filename, task)
+}
+
+//------------------------------------------------------------------------------
+
 // Show displays the task information as yaml
 func (task *Task) Show() (string, error) {
 	return util.ConvertToYAML(task)
@@ -15,6 +15,7 @@ import (
 //   - Name
 //   - Type
 //   - Versions
+//   - Retry
 //
 // Functions:
 //   - NewTemplate
@@ -58,9 +59,10 @@ func (m *VariantMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 // Template describes all desired configurations for a component within a domain.
 type Template struct {
-	Name     string     `yaml:"name"`     // name of the component
-	Type     string     `yaml:"type"`     // type of the component
-	Variants VariantMap `yaml:"variants"` // configuration of component
+	Name     string       `yaml:"name"`            // name of the component
+	Type     string       `yaml:"type"`            // type of the component
+	Variants VariantMap   `yaml:"variants"`        // configuration of component
+	Retry    *RetryPolicy `yaml:"retry,omitempty"` // retry policy for the transitions of all variants
 }
 
 //------------------------------------------------------------------------------
@@ -139,7 +141,7 @@ func (template *Template) AddVariant(variant *Variant) error {
 	// check if template has already been defined
 	template.Variants.RLock()
 	_, ok := template.Variants.Map[variant.Version]
-	template.Variants.Unlock()
+	template.Variants.RUnlock()
 
 	if ok {
 		return errors.New("variant already exists")
@@ -183,6 +185,12 @@ func (template *Template) DeleteVariant(name string) error {
 //   - Version
 //   - Configuration
 //   - Dependencies
+//   - Timeouts
+//   - Retry
+//   - Hooks
+//   - Readiness
+//   - Liveness
+//   - Reconfigure
 //
 // Functions:
 //   - NewVariant
@@ -195,6 +203,21 @@ func (template *Template) DeleteVariant(name string) error {
 //   - variant.GetDependency
 //   - variant.AddDependency
 //   - variant.DeleteDependency
+//
+//   - variant.GetTimeout
+//
+//   - variant.GetHook
+//   - variant.GetHooks
+//
+//   - variant.GetReconfigure
+//------------------------------------------------------------------------------
+
+// ReconfigureConfigure indicates that instances are reconfigured in place if their configuration has changed
+const ReconfigureConfigure string = "configure"
+
+// ReconfigureReplace indicates that instances are destroyed and created again if their configuration has changed
+const ReconfigureReplace string = "replace"
+
 //------------------------------------------------------------------------------
 
 // DependencyMap is a synchronized map for a map of dependencies
@@ -226,9 +249,15 @@ func (m *DependencyMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 // Variant describes a desired configurations for a component within a domain.
 type Variant struct {
-	Version       string        `yaml:"version"`       // name of the component
-	Configuration string        `yaml:"configuration"` // configuration of the component
-	Dependencies  DependencyMap `yaml:"dependencies"`  // dependencies of the component
+	Version       string         `yaml:"version"`               // name of the component
+	Configuration string         `yaml:"configuration"`         // configuration of the component
+	Dependencies  DependencyMap  `yaml:"dependencies"`          // dependencies of the component
+	Timeouts      map[string]int `yaml:"timeouts,omitempty"`    // timeouts in seconds per transition ("default" applies to all transitions)
+	Retry         *RetryPolicy   `yaml:"retry,omitempty"`       // retry policy for the transitions of the variant
+	Hooks         []*Hook        `yaml:"hooks,omitempty"`       // hooks executed before or after transitions
+	Readiness     *Probe         `yaml:"readiness,omitempty"`   // check whether a started instance is ready
+	Liveness      *Probe         `yaml:"liveness,omitempty"`    // periodic check whether an active instance is alive
+	Reconfigure   string         `yaml:"reconfigure,omitempty"` // handling of configuration changes (configure/replace)
 }
 
 //------------------------------------------------------------------------------
@@ -240,6 +269,8 @@ func NewVariant(name string, configuration string) (*Variant, error) {
 	variant.Version = name
 	variant.Configuration = configuration
 	variant.Dependencies = DependencyMap{Map: map[string]*Dependency{}}
+	variant.Timeouts = map[string]int{}
+	variant.Hooks = []*Hook{}
 
 	// success
 	return &variant, nil
@@ -302,6 +333,70 @@ func (variant *Variant) GetDependency(name string) (*Dependency, error) {
 
 //------------------------------------------------------------------------------
 
+// GetTimeout retrieves the timeout in seconds of a transition
+func (variant *Variant) GetTimeout(transition string) (int, error) {
+	// check for a transition specific timeout
+	timeout, ok := variant.Timeouts[transition]
+	if ok {
+		return timeout, nil
+	}
+
+	// check for a default timeout
+	timeout, ok = variant.Timeouts["default"]
+	if ok {
+		return timeout, nil
+	}
+
+	// no timeout has been defined
+	return 0, errors.New("timeout not found")
+}
+
+//------------------------------------------------------------------------------
+
+// GetHook retrieves a hook of a template variant by name
+func (variant *Variant) GetHook(name string) (*Hook, error) {
+	for _, hook := range variant.Hooks {
+		if hook.Name == name {
+			return hook, nil
+		}
+	}
+
+	return nil, errors.New("hook not found")
+}
+
+//------------------------------------------------------------------------------
+
+// GetHooks retrieves the hooks of a template variant which are executed in a
+// phase (pre/post) of a transition in the order of their definition
+func (variant *Variant) GetHooks(phase string, transition string) []*Hook {
+	hooks := []*Hook{}
+
+	for _, hook := range variant.Hooks {
+		if hook.Phase() == phase && hook.Transition() == transition {
+			hooks = append(hooks, hook)
+		}
+	}
+
+	return hooks
+}
+
+//------------------------------------------------------------------------------
+
+// GetReconfigure determines how instances of a template variant are adjusted
+// to a changed configuration (default: configure)
+func (variant *Variant) GetReconfigure() (string, error) {
+	switch variant.Reconfigure {
+	case "", ReconfigureConfigure:
+		return ReconfigureConfigure, nil
+	case ReconfigureReplace:
+		return ReconfigureReplace, nil
+	}
+
+	return "", errors.Errorf("unknown reconfiguration policy: '%s'", variant.Reconfigure)
+}
+
+//------------------------------------------------------------------------------
+
 // AddDependency adds a dependency to a variant of a template
 func (variant *Variant) AddDependency(dependency *Dependency) error {
 	// check if dependency has already been defined
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"tsai.eu/orchestrator/engine"
 	"tsai.eu/orchestrator/model"
@@ -22,9 +23,53 @@ func main() {
 	// create model
 	m := model.GetModel()
 
+	// load model
+	if filename := util.ModelFile(); filename != "" {
+		if err := m.Load(filename); err != nil {
+			fmt.Println("unable to load model:", err)
+		}
+	}
+
+	// restore tasks and events from the journal and continue journaling
+	if filename := util.JournalFile(); filename != "" {
+		if err := engine.ReplayJournal(m, filename); err != nil {
+			fmt.Println("unable to replay journal:", err)
+		}
+		if err := engine.OpenJournal(filename); err != nil {
+			fmt.Println("unable to open journal:", err)
+		}
+		if err := engine.CompactJournal(); err != nil {
+			fmt.Println("unable to compact journal:", err)
+		}
+	}
+
+	// detect configuration changes of instances without a fingerprint from now on
+	engine.RecordBaselineFingerprints(m)
+
 	// start the main event loop
 	engine.StartDispatcher(m)
 
+	// resume the execution of interrupted tasks
+	engine.ResumeTasks(m)
+
+	// start the scheduled architecture executions
+	engine.StartScheduler(m, 10*time.Second)
+
+	// start the reconciliation of the domains if requested
+	if interval := util.ReconcileInterval(); interval > 0 {
+		engine.StartReconciler(m, time.Duration(interval)*time.Second)
+	}
+
+	// start the liveness checks of the active instances if requested
+	if interval := util.LivenessInterval(); interval > 0 {
+		engine.StartLivenessMonitor(m, time.Duration(interval)*time.Second)
+	}
+
+	// start the detection of stalled tasks if requested
+	if interval := util.WatchdogInterval(); interval > 0 {
+		engine.StartWatchdog(m, time.Duration(interval)*time.Second, time.Duration(util.StallPeriod())*time.Second, util.RepairStalls())
+	}
+
 	// start the command line interface
 	shell.Run(m)
 }
@@ -1,6 +1,7 @@
 package shell
 
 import (
+	"errors"
 	"fmt"
 
 	ishell "gopkg.in/abiosoft/ishell.v2"
@@ -201,7 +202,7 @@ func ArchitectureCommand(context *ishell.Context, m *model.Model) {
 		}
 
 		// create task and start it by signalling an event
-		task, _ := engine.NewArchitectureTask(domain.Name, "", architecture)
+		task, err := engine.NewArchitectureTask(domain.Name, "", architecture)
 		if err != nil {
 			handleResult(context, err, "task can not be created", "")
 			return
@@ -214,6 +215,95 @@ func ArchitectureCommand(context *ishell.Context, m *model.Model) {
 		channel <- model.NewEvent(domain.Name, task.GetUUID(), model.EventTypeTaskExecution, "")
 
 		handleResult(context, nil, "architecture can not be executed", "architecture execution has been initiated")
+	case "plan":
+		// check availability of arguments
+		if len(context.Args) < 3 || len(context.Args) > 5 {
+			ArchitectureUsage(true, context)
+			return
+		}
+
+		// determine domain
+		domain, err := m.GetDomain(context.Args[1])
+
+		if err != nil {
+			handleResult(context, err, "domain can not be identified", "")
+			return
+		}
+
+		// determine architecture
+		architecture, err := domain.GetArchitecture(context.Args[2])
+
+		if err != nil {
+			handleResult(context, err, "architecture can not be identified", "")
+			return
+		}
+
+		// determine format
+		format := "text"
+		if len(context.Args) > 3 {
+			format = context.Args[3]
+		}
+
+		// determine the required changes
+		plan, err := engine.NewPlan(domain, architecture)
+
+		if err != nil {
+			handleResult(context, err, "plan can not be determined", "")
+			return
+		}
+
+		// save the plan (only yaml and json plans can be applied later on)
+		if len(context.Args) == 5 {
+			if format != "yaml" && format != "json" {
+				handleResult(context, errors.New("unsupported format: "+format), "plan can only be saved as yaml or json", "")
+				return
+			}
+
+			err = plan.Save(context.Args[4], format)
+			handleResult(context, err, "plan can not be saved", "plan has been saved")
+			return
+		}
+
+		// display the plan
+		result, err := plan.Show(format)
+		handleResult(context, err, "plan can not be displayed", result)
+	case "apply":
+		// check availability of arguments
+		if len(context.Args) != 2 {
+			ArchitectureUsage(true, context)
+			return
+		}
+
+		// load plan
+		plan := engine.Plan{}
+
+		err := plan.Load(context.Args[1])
+		if err != nil {
+			handleResult(context, err, "plan can not be loaded", "")
+			return
+		}
+
+		// refuse to execute outdated plans
+		err = plan.Verify()
+		if err != nil {
+			handleResult(context, err, "plan can not be applied", "")
+			return
+		}
+
+		// create task and start it by signalling an event
+		task, err := engine.NewPlanTask("", &plan)
+		if err != nil {
+			handleResult(context, err, "task can not be created", "")
+			return
+		}
+
+		// get event channel
+		channel := engine.GetEventChannel()
+
+		// create event
+		channel <- model.NewEvent(plan.Domain, task.GetUUID(), model.EventTypeTaskExecution, "")
+
+		handleResult(context, nil, "plan can not be applied", "plan execution has been initiated")
 	default:
 		ArchitectureUsage(true, context)
 	}
@@ -233,6 +323,9 @@ func ArchitectureUsage(header bool, context *ishell.Context) {
 	context.Println(`               show <domain> <architecture>`)
 	context.Println(`               delete <domain> <architecture>`)
 	context.Println(`               execute <domain> <architecture>`)
+	context.Println(`               plan <domain> <architecture> [text|yaml|json]`)
+	context.Println(`               plan <domain> <architecture> yaml|json <filename>`)
+	context.Println(`               apply <filename>`)
 }
 
 //------------------------------------------------------------------------------
@@ -1,7 +1,10 @@
 package shell
 
 import (
+	"strconv"
+
 	ishell "gopkg.in/abiosoft/ishell.v2"
+	"tsai.eu/orchestrator/engine"
 	"tsai.eu/orchestrator/model"
 	"tsai.eu/orchestrator/util"
 )
@@ -173,6 +176,55 @@ func ComponentCommand(context *ishell.Context, m *model.Model) {
 		// execute command
 		err = d.DeleteComponent(context.Args[2])
 		handleResult(context, err, "component can not be deleted", "component has been deleted")
+	case "promote", "abort":
+		// check availability of arguments
+		if len(context.Args) != 3 {
+			ComponentUsage(true, context)
+			return
+		}
+
+		// create task and start it by signalling an event
+		task, err := engine.NewReleaseTask(context.Args[1], context.Args[2], action)
+		if err != nil {
+			handleResult(context, err, "unable to "+action+" the release", "")
+			return
+		}
+
+		// get event channel
+		channel := engine.GetEventChannel()
+
+		// create event
+		channel <- model.NewEvent(task.Domain, task.GetUUID(), model.EventTypeTaskExecution, "")
+
+		handleResult(context, nil, "", "task has been initiated: "+task.GetUUID())
+	case "scale":
+		// check availability of arguments
+		if len(context.Args) != 5 {
+			ComponentUsage(true, context)
+			return
+		}
+
+		// determine size
+		size, err := strconv.Atoi(context.Args[4])
+		if err != nil {
+			handleResult(context, err, "invalid size", "")
+			return
+		}
+
+		// create task and start it by signalling an event
+		task, err := engine.NewScaleTask(context.Args[1], context.Args[2], context.Args[3], size)
+		if err != nil {
+			handleResult(context, err, "unable to scale the component", "")
+			return
+		}
+
+		// get event channel
+		channel := engine.GetEventChannel()
+
+		// create event
+		channel <- model.NewEvent(task.Domain, task.GetUUID(), model.EventTypeTaskExecution, "")
+
+		handleResult(context, nil, "", "task has been initiated: "+task.GetUUID())
 	default:
 		ComponentUsage(true, context)
 	}
@@ -191,6 +243,9 @@ func ComponentUsage(header bool, context *ishell.Context) {
 	context.Println(`            save <domain> <component> <filename>`)
 	context.Println(`            show <domain> <component>`)
 	context.Println(`            delete <domain> <component>`)
+	context.Println(`            promote <domain> <component>`)
+	context.Println(`            abort <domain> <component>`)
+	context.Println(`            scale <domain> <component> <version> <size>`)
 }
 
 //------------------------------------------------------------------------------
@@ -0,0 +1,81 @@
+package shell
+
+import (
+	"strconv"
+
+	ishell "gopkg.in/abiosoft/ishell.v2"
+	"tsai.eu/orchestrator/engine"
+	"tsai.eu/orchestrator/model"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+
+// DispatcherCommand executes the dispatcher related subcommands
+func DispatcherCommand(context *ishell.Context, m *model.Model) {
+	// check if the action has been defined
+	if len(context.Args) < 1 {
+		DispatcherUsage(true, context)
+		return
+	}
+
+	// determine the required action
+	action := context.Args[0]
+
+	// handle required action
+	switch action {
+	case "?":
+		DispatcherUsage(true, context)
+	case "stats":
+		// check availability of arguments
+		if len(context.Args) != 1 {
+			DispatcherUsage(true, context)
+			return
+		}
+
+		// execute command
+		result, err := util.ConvertToYAML(engine.GetWorkerPool().Statistics())
+		handleResult(context, err, "statistics can not be displayed", result)
+	case "limit":
+		// check availability of arguments
+		if len(context.Args) != 4 {
+			DispatcherUsage(true, context)
+			return
+		}
+
+		// determine limit
+		limit, err := strconv.Atoi(context.Args[3])
+		if err != nil {
+			handleResult(context, err, "invalid limit", "")
+			return
+		}
+
+		// execute command
+		switch context.Args[1] {
+		case "domain":
+			err = engine.GetWorkerPool().SetDomainLimit(context.Args[2], limit)
+		case "controller":
+			err = engine.GetWorkerPool().SetControllerLimit(context.Args[2], limit)
+		default:
+			DispatcherUsage(true, context)
+			return
+		}
+		handleResult(context, err, "limit can not be defined", "limit has been defined")
+	default:
+		DispatcherUsage(true, context)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// DispatcherUsage describes how to make use of the subcommand
+func DispatcherUsage(header bool, context *ishell.Context) {
+	if header {
+		context.Println("usage:")
+	}
+	context.Println(`  dispatcher stats`)
+	context.Println(`             limit domain <domain> <limit>`)
+	context.Println(`             limit controller <type> <limit>`)
+}
+
+//------------------------------------------------------------------------------
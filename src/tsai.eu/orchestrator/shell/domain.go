@@ -2,6 +2,7 @@ package shell
 
 import (
 	ishell "gopkg.in/abiosoft/ishell.v2"
+	"tsai.eu/orchestrator/engine"
 	"tsai.eu/orchestrator/model"
 	"tsai.eu/orchestrator/util"
 )
@@ -103,6 +104,101 @@ func DomainCommand(context *ishell.Context, m *model.Model) {
 		// execute command
 		err := m.DeleteDomain(context.Args[1])
 		handleResult(context, err, "domain can not be deleted", "domain has been deleted")
+	case "reconcile":
+		// check availability of arguments
+		if len(context.Args) < 2 || len(context.Args) > 3 {
+			DomainUsage(true, context)
+			return
+		}
+
+		// determine domain
+		domain, err := m.GetDomain(context.Args[1])
+
+		if err != nil {
+			handleResult(context, err, "domain can not be identified", "")
+			return
+		}
+
+		// switch automatic correction of drift on or off
+		if len(context.Args) == 3 {
+			switch context.Args[2] {
+			case "on":
+				domain.Reconcile = true
+			case "off":
+				domain.Reconcile = false
+			default:
+				DomainUsage(true, context)
+				return
+			}
+
+			handleResult(context, nil, "", "reconciliation has been switched "+context.Args[2])
+			return
+		}
+
+		// reconcile the domain
+		events, err := engine.ReconcileDomain(domain)
+		if err != nil {
+			handleResult(context, err, "domain can not be reconciled", "")
+			return
+		}
+
+		details := []string{}
+		for _, event := range events {
+			details = append(details, event.Detail)
+		}
+
+		result, err := util.ConvertToYAML(details)
+		handleResult(context, err, "drift can not be displayed", result)
+	case "liveness":
+		// check availability of arguments
+		if len(context.Args) != 2 {
+			DomainUsage(true, context)
+			return
+		}
+
+		// determine domain
+		domain, err := m.GetDomain(context.Args[1])
+
+		if err != nil {
+			handleResult(context, err, "domain can not be identified", "")
+			return
+		}
+
+		// check the liveness of the running instances
+		details := []string{}
+		for _, event := range engine.CheckLiveness(domain) {
+			details = append(details, event.Detail)
+		}
+
+		result, err := util.ConvertToYAML(details)
+		handleResult(context, err, "failures can not be displayed", result)
+	case "rollback":
+		// check availability of arguments
+		if len(context.Args) != 3 {
+			DomainUsage(true, context)
+			return
+		}
+
+		// determine domain
+		domain, err := m.GetDomain(context.Args[1])
+
+		if err != nil {
+			handleResult(context, err, "domain can not be identified", "")
+			return
+		}
+
+		// switch automatic rollback of failed architecture executions on or off
+		switch context.Args[2] {
+		case "on":
+			domain.Rollback = true
+		case "off":
+			domain.Rollback = false
+		default:
+			DomainUsage(true, context)
+			return
+		}
+
+		handleResult(context, nil, "", "rollback has been switched "+context.Args[2])
 	default:
 		DomainUsage(true, context)
 	}
@@ -121,6 +217,9 @@ func DomainUsage(header bool, context *ishell.Context) {
 	context.Println("         load <filename>")
 	context.Println("         save <domain> <filename>")
 	context.Println("         delete <domain>")
+	context.Println("         reconcile <domain> [on|off]")
+	context.Println("         liveness <domain>")
+	context.Println("         rollback <domain> on|off")
 }
 
 //------------------------------------------------------------------------------
@@ -1,7 +1,11 @@
 package shell
 
 import (
+	"fmt"
+	"time"
+
 	ishell "gopkg.in/abiosoft/ishell.v2"
+	"tsai.eu/orchestrator/engine"
 	"tsai.eu/orchestrator/model"
 	"tsai.eu/orchestrator/util"
 )
@@ -171,6 +175,33 @@ func EventCommand(context *ishell.Context, m *model.Model) {
 		// execute command
 		err = domain.DeleteEvent(context.Args[2])
 		handleResult(context, err, "event can not be deleted", "event has been deleted")
+	case "tail":
+		// check availability of arguments
+		if len(context.Args) < 2 {
+			EventUsage(true, context)
+			return
+		}
+
+		// get domain
+		_, err := m.GetDomain(context.Args[1])
+
+		if err != nil {
+			handleResult(context, err, "domain can not be identified", "")
+			return
+		}
+
+		// determine filter: event types and optionally a task
+		filter := engine.EventFilter{Domain: context.Args[1]}
+		for _, arg := range context.Args[2:] {
+			eventType, err := model.String2EventType(arg)
+			if err != nil {
+				filter.Task = arg
+				continue
+			}
+			filter.Types = append(filter.Types, eventType)
+		}
+
+		tailEvents(context, filter)
 	default:
 		EventUsage(true, context)
 	}
@@ -178,6 +209,41 @@ func EventCommand(context *ishell.Context, m *model.Model) {
 
 //------------------------------------------------------------------------------
 
+// tailEvents displays the events matching a filter as they are published
+// until the user presses enter.
+func tailEvents(context *ishell.Context, filter engine.EventFilter) {
+	bus := engine.GetEventBus()
+	subscription := bus.Subscribe(filter, 256)
+
+	done := make(chan bool)
+	go func() {
+		for event := range subscription.Events {
+			line := fmt.Sprintf("%s %-11s task=%s source=%s",
+				time.Unix(0, event.Time).Format("15:04:05.000"),
+				event.Type,
+				event.Task,
+				event.Source)
+			if event.Detail != "" {
+				line = line + " detail: " + event.Detail
+			}
+			context.Println(line)
+		}
+		done <- true
+	}()
+
+	context.Println("tailing events - press enter to stop")
+	context.ReadLine()
+
+	bus.Unsubscribe(subscription)
+	<-done
+
+	if subscription.Dropped > 0 {
+		context.Printf("%d events have been dropped\n", subscription.Dropped)
+	}
+}
+
+//------------------------------------------------------------------------------
+
 // EventUsage describes how to make use of the subcommand
 func EventUsage(header bool, context *ishell.Context) {
 	if header {
@@ -189,6 +255,7 @@ func EventUsage(header bool, context *ishell.Context) {
 	context.Println(`        save <domain> <event> <filename>`)
 	context.Println(`        show <domain> <event>`)
 	context.Println(`        delete <domain> <event>`)
+	context.Println(`        tail <domain> [<task>] [<type>...]`)
 }
 
 //------------------------------------------------------------------------------
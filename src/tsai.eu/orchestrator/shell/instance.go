@@ -2,6 +2,7 @@ package shell
 
 import (
 	ishell "gopkg.in/abiosoft/ishell.v2"
+	"tsai.eu/orchestrator/engine"
 	"tsai.eu/orchestrator/model"
 	"tsai.eu/orchestrator/util"
 )
@@ -213,6 +214,27 @@ func InstanceCommand(context *ishell.Context, m *model.Model) {
 		// execute command
 		err = component.DeleteInstance(context.Args[3])
 		handleResult(context, err, "instance can not be deleted", "instance has been deleted")
+	case "transition":
+		// check availability of arguments
+		if len(context.Args) != 5 {
+			InstanceUsage(true, context)
+			return
+		}
+
+		// create task and start it by signalling an event
+		task, err := engine.NewInstanceTransitionTask(context.Args[1], context.Args[2], context.Args[3], context.Args[4])
+		if err != nil {
+			handleResult(context, err, "unable to transition the instance", "")
+			return
+		}
+
+		// get event channel
+		channel := engine.GetEventChannel()
+
+		// create event
+		channel <- model.NewEvent(task.Domain, task.GetUUID(), model.EventTypeTaskExecution, "")
+
+		handleResult(context, nil, "", "task has been initiated: "+task.GetUUID())
 	default:
 		InstanceUsage(true, context)
 	}
@@ -231,6 +253,7 @@ func InstanceUsage(header bool, context *ishell.Context) {
 	context.Println(`           save <domain> <component> <instance> <filename>`)
 	context.Println(`           show <domain> <component> <instance>`)
 	context.Println(`           delete <domain> <component> <instance>`)
+	context.Println(`           transition <domain> <component> <instance> <state>`)
 }
 
 //------------------------------------------------------------------------------
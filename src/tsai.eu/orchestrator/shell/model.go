@@ -2,6 +2,7 @@ package shell
 
 import (
 	ishell "gopkg.in/abiosoft/ishell.v2"
+	"tsai.eu/orchestrator/engine"
 	"tsai.eu/orchestrator/model"
 )
 
@@ -27,6 +28,9 @@ func ModelCommand(context *ishell.Context, m *model.Model) {
 		handleResult(context, err, "model could not be reset", "model has been reset")
 	case "load":
 		err := m.Load(context.Args[1])
+		if err == nil {
+			engine.RecordBaselineFingerprints(m)
+		}
 		handleResult(context, err, "model could not be loaded", "model has been loaded")
 	case "save":
 		err := m.Save(context.Args[1])
@@ -0,0 +1,191 @@
+package shell
+
+import (
+	"strings"
+
+	ishell "gopkg.in/abiosoft/ishell.v2"
+	"tsai.eu/orchestrator/model"
+	"tsai.eu/orchestrator/util"
+)
+
+//------------------------------------------------------------------------------
+
+// ScheduleCommand executes the schedule related subcommands
+func ScheduleCommand(context *ishell.Context, m *model.Model) {
+	// check if the action has been defined
+	if len(context.Args) < 1 {
+		ScheduleUsage(true, context)
+		return
+	}
+
+	// determine the required action
+	action := context.Args[0]
+
+	// handle required action
+	switch action {
+	case "?":
+		ScheduleUsage(true, context)
+	case "list":
+		// check availability of arguments
+		if len(context.Args) != 2 {
+			ScheduleUsage(true, context)
+			return
+		}
+
+		// get domain
+		domain, err := m.GetDomain(context.Args[1])
+
+		if err != nil {
+			handleResult(context, err, "domain can not be identified", "")
+			return
+		}
+
+		// list schedules
+		schedules, _ := domain.ListSchedules()
+		result, err := util.ConvertToJSON(schedules)
+		handleResult(context, err, "schedules could not be listed", result)
+	case "create":
+		// check availability of arguments
+		if len(context.Args) < 4 {
+			ScheduleUsage(true, context)
+			return
+		}
+
+		// get domain
+		domain, err := m.GetDomain(context.Args[1])
+
+		if err != nil {
+			handleResult(context, err, "domain can not be identified", "")
+			return
+		}
+
+		// check architecture
+		_, err = domain.GetArchitecture(context.Args[2])
+
+		if err != nil {
+			handleResult(context, err, "architecture can not be identified", "")
+			return
+		}
+
+		// determine cron expression and optional policy
+		fields := context.Args[3:]
+		policy := model.SchedulePolicySkip
+		if last := fields[len(fields)-1]; last == model.SchedulePolicySkip || last == model.SchedulePolicyQueue {
+			policy = last
+			fields = fields[:len(fields)-1]
+		}
+
+		// create schedule
+		schedule, err := model.NewSchedule(context.Args[2], strings.Trim(strings.Join(fields, " "), `"'`))
+		if err != nil {
+			handleResult(context, err, "invalid schedule", "")
+			return
+		}
+		schedule.Policy = policy
+
+		// add schedule to domain
+		err = domain.AddSchedule(schedule)
+		handleResult(context, err, "unable to create schedule", schedule.UUID)
+	case "show", "history":
+		// check availability of arguments
+		if len(context.Args) != 3 {
+			ScheduleUsage(true, context)
+			return
+		}
+
+		// get domain
+		domain, err := m.GetDomain(context.Args[1])
+
+		if err != nil {
+			handleResult(context, err, "domain can not be identified", "")
+			return
+		}
+
+		// get schedule
+		schedule, err := domain.GetSchedule(context.Args[2])
+
+		if err != nil {
+			handleResult(context, err, "schedule can not be identified", "")
+			return
+		}
+
+		// execute the command
+		var result string
+		if action == "show" {
+			result, err = schedule.Show()
+		} else {
+			result, err = util.ConvertToYAML(schedule.History)
+		}
+		handleResult(context, err, "schedule can not be displayed", result)
+	case "policy":
+		// check availability of arguments
+		if len(context.Args) != 4 {
+			ScheduleUsage(true, context)
+			return
+		}
+
+		// get domain
+		domain, err := m.GetDomain(context.Args[1])
+
+		if err != nil {
+			handleResult(context, err, "domain can not be identified", "")
+			return
+		}
+
+		// get schedule
+		schedule, err := domain.GetSchedule(context.Args[2])
+
+		if err != nil {
+			handleResult(context, err, "schedule can not be identified", "")
+			return
+		}
+
+		// define the behaviour if the previous run is still executing
+		switch context.Args[3] {
+		case model.SchedulePolicySkip, model.SchedulePolicyQueue:
+			schedule.Policy = context.Args[3]
+		default:
+			ScheduleUsage(true, context)
+			return
+		}
+
+		handleResult(context, nil, "", "policy has been defined")
+	case "delete":
+		// check availability of arguments
+		if len(context.Args) != 3 {
+			ScheduleUsage(true, context)
+			return
+		}
+
+		// get domain
+		domain, err := m.GetDomain(context.Args[1])
+
+		if err != nil {
+			handleResult(context, err, "domain can not be identified", "")
+			return
+		}
+
+		// execute command
+		err = domain.DeleteSchedule(context.Args[2])
+		handleResult(context, err, "schedule can not be deleted", "schedule has been deleted")
+	default:
+		ScheduleUsage(true, context)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// ScheduleUsage describes how to make use of the subcommand
+func ScheduleUsage(header bool, context *ishell.Context) {
+	if header {
+		context.Println("usage:")
+	}
+	context.Println(`  schedule list <domain>`)
+	context.Println(`           create <domain> <architecture> <minute> <hour> <day> <month> <weekday> [skip|queue]`)
+	context.Println(`           show <domain> <schedule>`)
+	context.Println(`           history <domain> <schedule>`)
+	context.Println(`           policy <domain> <schedule> skip|queue`)
+	context.Println(`           delete <domain> <schedule>`)
+}
+
+//------------------------------------------------------------------------------
@@ -2,6 +2,7 @@ package shell
 
 import (
 	ishell "gopkg.in/abiosoft/ishell.v2"
+	"tsai.eu/orchestrator/engine"
 	"tsai.eu/orchestrator/model"
 	"tsai.eu/orchestrator/util"
 )
@@ -213,6 +214,27 @@ func ServiceCommand(context *ishell.Context, m *model.Model) {
 		// execute command
 		err = architecture.DeleteService(context.Args[3])
 		handleResult(context, err, "service can not be deleted", "service has been deleted")
+	case "execute":
+		// check availability of arguments
+		if len(context.Args) != 4 {
+			ServiceUsage(true, context)
+			return
+		}
+
+		// create task and start it by signalling an event
+		task, err := engine.NewServiceExecutionTask(context.Args[1], context.Args[2], context.Args[3])
+		if err != nil {
+			handleResult(context, err, "service can not be executed", "")
+			return
+		}
+
+		// get event channel
+		channel := engine.GetEventChannel()
+
+		// create event
+		channel <- model.NewEvent(task.Domain, task.GetUUID(), model.EventTypeTaskExecution, "")
+
+		handleResult(context, nil, "", "task has been initiated: "+task.GetUUID())
 	default:
 		ServiceUsage(true, context)
 	}
@@ -231,6 +253,7 @@ func ServiceUsage(header bool, context *ishell.Context) {
 	context.Println(`          save <domain> <architecture> <service> <filename>`)
 	context.Println(`          show <domain> <architecture> <service>`)
 	context.Println(`          delete <domain> <architecture> <service>`)
+	context.Println(`          execute <domain> <architecture> <service>`)
 }
 
 //------------------------------------------------------------------------------
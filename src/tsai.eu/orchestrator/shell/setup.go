@@ -1,6 +1,7 @@
 package shell
 
 import (
+	"errors"
 	"strconv"
 
 	ishell "gopkg.in/abiosoft/ishell.v2"
@@ -91,6 +92,17 @@ func SetupCommand(context *ishell.Context, m *model.Model) {
 			return
 		}
 
+		// check state against the lifecycle of the component type
+		componentType := ""
+		if template, err := domain.GetTemplate(service.Name); err == nil {
+			componentType = template.Type
+		}
+
+		if !model.IsValidComponentState(componentType, context.Args[6]) {
+			handleResult(context, errors.New("invalid state"), "state is not supported by the component type", "")
+			return
+		}
+
 		// create new setup (name, version, state, size)
 		size, err := strconv.Atoi(context.Args[7])
 		if err != nil {
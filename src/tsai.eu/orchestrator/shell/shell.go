@@ -33,6 +33,8 @@ func Run(m *model.Model) {
 			InstanceUsage(false, c)
 			TaskUsage(false, c)
 			EventUsage(false, c)
+			DispatcherUsage(false, c)
+			ScheduleUsage(false, c)
 		},
 	})
 
@@ -120,6 +122,20 @@ func Run(m *model.Model) {
 		Func: func(c *ishell.Context) { EventCommand(c, m) },
 	})
 
+	// register a function for the "dispatcher" command.
+	shell.AddCmd(&ishell.Cmd{
+		Name: "dispatcher",
+		Help: "dispatcher commands",
+		Func: func(c *ishell.Context) { DispatcherCommand(c, m) },
+	})
+
+	// register a function for the "schedule" command.
+	shell.AddCmd(&ishell.Cmd{
+		Name: "schedule",
+		Help: "schedule commands",
+		Func: func(c *ishell.Context) { ScheduleCommand(c, m) },
+	})
+
 	// register a function for "#" command.
 	shell.AddCmd(&ishell.Cmd{
 		Name: "comment",
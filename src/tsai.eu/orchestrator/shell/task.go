@@ -2,8 +2,11 @@ package shell
 
 import (
 	"errors"
+	"strings"
+	"time"
 
 	ishell "gopkg.in/abiosoft/ishell.v2"
+	"tsai.eu/orchestrator/engine"
 	"tsai.eu/orchestrator/model"
 	"tsai.eu/orchestrator/util"
 )
@@ -73,17 +76,32 @@ func TaskCommand(context *ishell.Context, m *model.Model) {
 		}
 
 		// get domain
-		_, err := m.GetDomain(context.Args[1])
+		domain, err := m.GetDomain(context.Args[1])
 
 		if err != nil {
 			handleResult(context, err, "domain can not be identified", "")
 			return
 		}
 
-		// TODO: create new task, load from file and add it to domain
+		// load task
+		var task model.Task
 
-		// add template to domain
-		err = errors.New("not implemented")
+		err = task.Load(context.Args[2])
+		if err != nil {
+			handleResult(context, err, "task could not be loaded", "")
+			return
+		}
+		task.Domain = domain.Name
+
+		// attach the behaviour of the task type
+		err = engine.BindHandlers(&task)
+		if err != nil {
+			handleResult(context, err, "task type can not be identified", "")
+			return
+		}
+
+		// add task to domain
+		err = domain.AddTask(&task)
 		handleResult(context, err, "unable to load task", "task has been loaded")
 	case "save":
 		// check availability of arguments
@@ -136,7 +154,135 @@ func TaskCommand(context *ishell.Context, m *model.Model) {
 
 		// execute the command
 		result, err := task.Show()
+
+		// add the lock of the instance addressed by the task
+		if lock, lerr := engine.GetLockManager().GetLock(task); lerr == nil && task.Instance != "" && err == nil {
+			info, _ := util.ConvertToYAML(map[string]*engine.InstanceLock{"lock": lock})
+			result = result + info
+		}
 		handleResult(context, err, "task can not be displayed", result)
+	case "tree":
+		// check availability of arguments
+		if len(context.Args) != 3 {
+			TaskUsage(true, context)
+			return
+		}
+
+		// execute the command
+		result, err := engine.TaskTree(context.Args[1], context.Args[2])
+		handleResult(context, err, "task tree can not be displayed", result)
+	case "watch":
+		// check availability of arguments
+		if len(context.Args) != 3 {
+			TaskUsage(true, context)
+			return
+		}
+
+		// get domain
+		domain, err := m.GetDomain(context.Args[1])
+
+		if err != nil {
+			handleResult(context, err, "domain can not be identified", "")
+			return
+		}
+
+		// get task
+		task, err := domain.GetTask(context.Args[2])
+
+		if err != nil {
+			handleResult(context, err, "task can not be identified", "")
+			return
+		}
+
+		// execute the command
+		watchTask(context, task)
+	case "approve":
+		// check availability of arguments
+		if len(context.Args) != 3 {
+			TaskUsage(true, context)
+			return
+		}
+
+		// execute the command
+		err := engine.ApproveTask(context.Args[1], context.Args[2])
+		handleResult(context, err, "task can not be approved", "task has been approved")
+	case "reject":
+		// check availability of arguments
+		if len(context.Args) < 3 {
+			TaskUsage(true, context)
+			return
+		}
+
+		// execute the command
+		err := engine.RejectTask(context.Args[1], context.Args[2], strings.Join(context.Args[3:], " "))
+		handleResult(context, err, "task can not be rejected", "task has been rejected")
+	case "cancel":
+		// check availability of arguments
+		if len(context.Args) != 3 {
+			TaskUsage(true, context)
+			return
+		}
+
+		// execute the command
+		err := engine.CancelTask(context.Args[1], context.Args[2])
+		handleResult(context, err, "task can not be cancelled", "task has been cancelled")
+	case "pause":
+		// check availability of arguments
+		if len(context.Args) != 3 {
+			TaskUsage(true, context)
+			return
+		}
+
+		// execute the command
+		err := engine.PauseTask(context.Args[1], context.Args[2])
+		handleResult(context, err, "task can not be paused", "task has been paused")
+	case "resume":
+		// check availability of arguments
+		if len(context.Args) != 3 {
+			TaskUsage(true, context)
+			return
+		}
+
+		// execute the command
+		err := engine.ResumeTask(context.Args[1], context.Args[2])
+		handleResult(context, err, "task can not be resumed", "task has been resumed")
+	case "stalls":
+		// check availability of arguments
+		if len(context.Args) != 2 && (len(context.Args) != 3 || context.Args[2] != "repair") {
+			TaskUsage(true, context)
+			return
+		}
+
+		// get domain
+		domain, err := m.GetDomain(context.Args[1])
+
+		if err != nil {
+			handleResult(context, err, "domain can not be identified", "")
+			return
+		}
+
+		// execute the command
+		stalls := engine.CheckStalls(domain, time.Duration(util.StallPeriod())*time.Second, len(context.Args) == 3)
+
+		result := "no stalled tasks"
+		if len(stalls) > 0 {
+			lines := []string{}
+			for _, stall := range stalls {
+				lines = append(lines, stall.Kind+": "+stall.Detail)
+			}
+			result = strings.Join(lines, "\n")
+		}
+		handleResult(context, nil, "", result)
+	case "types":
+		// check availability of arguments
+		if len(context.Args) != 1 {
+			TaskUsage(true, context)
+			return
+		}
+
+		// list task types
+		result, err := util.ConvertToJSON(engine.ListTaskTypes())
+		handleResult(context, err, "task types could not be listed", result)
 	case "delete":
 		// check availability of arguments
 		if len(context.Args) != 3 {
@@ -172,7 +318,72 @@ func TaskUsage(header bool, context *ishell.Context) {
 	context.Println(`       load <domain> <filename>`)
 	context.Println(`       save <domain> <task> <filename>`)
 	context.Println(`       show <domain> <task>`)
+	context.Println(`       tree <domain> <task>`)
+	context.Println(`       watch <domain> <task>`)
+	context.Println(`       approve <domain> <task>`)
+	context.Println(`       reject <domain> <task> [<reason>]`)
+	context.Println(`       cancel <domain> <task>`)
+	context.Println(`       pause <domain> <task>`)
+	context.Println(`       resume <domain> <task>`)
+	context.Println(`       stalls <domain> [repair]`)
 	context.Println(`       delete <domain> <task>`)
+	context.Println(`       types`)
+}
+
+//------------------------------------------------------------------------------
+
+// watchTask renders the hierarchy of a task whenever an event of its domain
+// has been handled until the task has finished or enter has been pressed.
+func watchTask(context *ishell.Context, task *model.Task) {
+	bus := engine.GetEventBus()
+	subscription := bus.Subscribe(engine.EventFilter{Domain: task.Domain}, 256)
+
+	stop := make(chan bool)
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+
+		// refresh at most twice per second
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+
+		changed := true
+		for {
+			select {
+			case <-stop:
+				return
+			case <-subscription.Events:
+				changed = true
+			case <-ticker.C:
+				finished := task.GetStatus() > model.TaskStatusExecuting
+				if !changed && !finished {
+					continue
+				}
+				changed = false
+
+				result, err := engine.TaskTree(task.Domain, task.UUID)
+				if err != nil {
+					handleResult(context, err, "task tree can not be displayed", "")
+					return
+				}
+				context.Println(result)
+
+				// stop once the task has finished
+				if finished {
+					context.Println("task has finished - press enter to continue")
+					return
+				}
+			}
+		}
+	}()
+
+	context.Println("watching task - press enter to stop")
+	context.ReadLine()
+
+	close(stop)
+	<-done
+
+	bus.Unsubscribe(subscription)
 }
 
 //------------------------------------------------------------------------------
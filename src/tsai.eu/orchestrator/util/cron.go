@@ -0,0 +1,183 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//------------------------------------------------------------------------------
+
+// CronExpression is a parsed cron expression with the five fields minute,
+// hour, day of month, month and day of week. Each field supports '*', single
+// values, ranges ('1-5'), lists ('1,15') and steps ('*/10', '0-30/5').
+type CronExpression struct {
+	Minutes  map[int]bool // minutes (0-59)
+	Hours    map[int]bool // hours (0-23)
+	Days     map[int]bool // days of the month (1-31)
+	Months   map[int]bool // months (1-12)
+	Weekdays map[int]bool // days of the week (0-6, 0 = Sunday)
+	anyDay   bool         // day of the month starts with '*'
+	anyWeek  bool         // day of the week starts with '*'
+}
+
+//------------------------------------------------------------------------------
+
+// ParseCron parses a cron expression consisting of five fields.
+func ParseCron(expression string) (*CronExpression, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("cron expression requires five fields: '%s'", expression)
+	}
+
+	var cron CronExpression
+	var err error
+
+	if cron.Minutes, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, errors.Wrap(err, "invalid minute")
+	}
+	if cron.Hours, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, errors.Wrap(err, "invalid hour")
+	}
+	if cron.Days, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, errors.Wrap(err, "invalid day of month")
+	}
+	if cron.Months, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, errors.Wrap(err, "invalid month")
+	}
+	if cron.Weekdays, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, errors.Wrap(err, "invalid day of week")
+	}
+
+	// 7 is an alias for Sunday
+	if cron.Weekdays[7] {
+		cron.Weekdays[0] = true
+		delete(cron.Weekdays, 7)
+	}
+
+	cron.anyDay = strings.HasPrefix(fields[2], "*")
+	cron.anyWeek = strings.HasPrefix(fields[4], "*")
+
+	// success
+	return &cron, nil
+}
+
+//------------------------------------------------------------------------------
+
+// parseCronField determines the values of a single field of a cron expression.
+func parseCronField(field string, low int, high int) (map[int]bool, error) {
+	values := map[int]bool{}
+
+	for _, part := range strings.Split(field, ",") {
+		// determine step
+		step := 1
+		if index := strings.Index(part, "/"); index >= 0 {
+			value, err := strconv.Atoi(part[index+1:])
+			if err != nil || value <= 0 {
+				return nil, errors.Errorf("invalid step: '%s'", part)
+			}
+			step = value
+			part = part[:index]
+		}
+
+		// determine range
+		from, to := low, high
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+
+			first, err1 := strconv.Atoi(bounds[0])
+			last, err2 := strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return nil, errors.Errorf("invalid range: '%s'", part)
+			}
+			from, to = first, last
+		default:
+			value, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, errors.Errorf("invalid value: '%s'", part)
+			}
+			from, to = value, value
+
+			// a single value with a step covers the remainder of the range
+			if step > 1 {
+				to = high
+			}
+		}
+
+		if from < low || to > high || from > to {
+			return nil, errors.Errorf("value out of range %d-%d: '%s'", low, high, part)
+		}
+
+		for value := from; value <= to; value += step {
+			values[value] = true
+		}
+	}
+
+	// success
+	return values, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Matches checks if a point in time (with a precision of minutes) is covered
+// by the cron expression. If both the day of the month and the day of the
+// week are restricted, either of them needs to match.
+func (cron *CronExpression) Matches(t time.Time) bool {
+	if !cron.Minutes[t.Minute()] || !cron.Hours[t.Hour()] || !cron.Months[int(t.Month())] {
+		return false
+	}
+
+	return cron.matchesDay(t)
+}
+
+//------------------------------------------------------------------------------
+
+// Next determines the first point in time after t which is covered by the
+// cron expression. The zero time is returned if there is none within the
+// next five years.
+func (cron *CronExpression) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for next.Before(limit) {
+		// skip whole days and hours which can not match
+		switch {
+		case !cron.Months[int(next.Month())]:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !cron.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case !cron.Hours[next.Hour()]:
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+		case !cron.Minutes[next.Minute()]:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+//------------------------------------------------------------------------------
+
+// matchesDay checks if the day of a point in time is covered by the cron expression.
+func (cron *CronExpression) matchesDay(t time.Time) bool {
+	day := cron.Days[t.Day()]
+	weekday := cron.Weekdays[int(t.Weekday())]
+
+	switch {
+	case cron.anyDay && cron.anyWeek:
+		return true
+	case cron.anyDay:
+		return weekday
+	case cron.anyWeek:
+		return day
+	}
+	return day || weekday
+}
+
+//------------------------------------------------------------------------------
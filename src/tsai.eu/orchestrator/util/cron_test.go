@@ -0,0 +1,139 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expression string
+		minutes    map[int]bool
+		weekdays   map[int]bool
+		fails      bool
+	}{
+		{expression: "*/20 * * * *", minutes: map[int]bool{0: true, 20: true, 40: true}},
+		{expression: "5/20 * * * *", minutes: map[int]bool{5: true, 25: true, 45: true}},
+		{expression: "1,3-5 * * * *", minutes: map[int]bool{1: true, 3: true, 4: true, 5: true}},
+		{expression: "0-30/10 * * * *", minutes: map[int]bool{0: true, 10: true, 20: true, 30: true}},
+		{expression: "0 * * * 7", minutes: map[int]bool{0: true}, weekdays: map[int]bool{0: true}},
+		{expression: "0 * * * 5-7", minutes: map[int]bool{0: true}, weekdays: map[int]bool{0: true, 5: true, 6: true}},
+		{expression: "* * * *", fails: true},
+		{expression: "* * * * * *", fails: true},
+		{expression: "60 * * * *", fails: true},
+		{expression: "* 24 * * *", fails: true},
+		{expression: "* * 0 * *", fails: true},
+		{expression: "* * * 13 *", fails: true},
+		{expression: "* * * * 8", fails: true},
+		{expression: "*/0 * * * *", fails: true},
+		{expression: "a * * * *", fails: true},
+		{expression: "5-1 * * * *", fails: true},
+		{expression: "1-a * * * *", fails: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			cron, err := ParseCron(test.expression)
+			if test.fails {
+				if err == nil {
+					t.Errorf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(cron.Minutes, test.minutes) {
+				t.Errorf("expected minutes %v, got %v", test.minutes, cron.Minutes)
+			}
+			if test.weekdays != nil && !reflect.DeepEqual(cron.Weekdays, test.weekdays) {
+				t.Errorf("expected weekdays %v, got %v", test.weekdays, cron.Weekdays)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestCronMatches(t *testing.T) {
+	// Monday, 15th of January 2024
+	monday := time.Date(2024, time.January, 15, 10, 7, 0, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{"* * * * *", true},
+		{"7 10 * * *", true},
+		{"8 10 * * *", false},
+		{"7 10 * * 1", true},
+		{"7 10 * * 2", false},
+		{"7 10 15 * *", true},
+		{"7 10 16 * *", false},
+		{"7 10 16 * 1", true},
+		{"7 10 15 * 2", true},
+		{"7 10 16 * 2", false},
+		{"7 10 * 2 *", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			cron, err := ParseCron(test.expression)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if matches := cron.Matches(monday); matches != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, matches)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestCronNext(t *testing.T) {
+	// Monday, 15th of January 2024
+	now := time.Date(2024, time.January, 15, 10, 7, 30, 0, time.UTC)
+
+	at := func(year int, month time.Month, day int, hour int, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expression string
+		expected   time.Time
+	}{
+		{"* * * * *", at(2024, time.January, 15, 10, 8)},
+		{"*/15 * * * *", at(2024, time.January, 15, 10, 15)},
+		{"0-10/5 * * * *", at(2024, time.January, 15, 10, 10)},
+		{"0 * * * *", at(2024, time.January, 15, 11, 0)},
+		{"7 10 * * *", at(2024, time.January, 16, 10, 7)},
+		{"30 9 * * *", at(2024, time.January, 16, 9, 30)},
+		{"1,2 9-17 * * 1-5", at(2024, time.January, 15, 11, 1)},
+		{"0 0 1 * *", at(2024, time.February, 1, 0, 0)},
+		{"0 12 * * 0", at(2024, time.January, 21, 12, 0)},
+		{"0 12 * * 7", at(2024, time.January, 21, 12, 0)},
+		{"0 12 13 * 5", at(2024, time.January, 19, 12, 0)},
+		{"0 0 29 2 *", at(2024, time.February, 29, 0, 0)},
+		{"0 0 1 1 *", at(2025, time.January, 1, 0, 0)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			cron, err := ParseCron(test.expression)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if next := cron.Next(now); !next.Equal(test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, next)
+			}
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -2,17 +2,48 @@ package util
 
 import (
 	"flag"
+	"fmt"
+	"os"
 )
 
 var debug *bool
+var reconcile *int
+var liveness *int
+var watchdog *int
+var stallPeriod *int
+var repairStalls *bool
+var modelFile *string
+var journalFile *string
+var workers *int
+var domainWorkers *int
+var controllerWorkers *int
+var lockMode *string
 
 //------------------------------------------------------------------------------
 
 // ParseCommandLineOptions parses the options of the CLI
 func ParseCommandLineOptions() {
 	debug = flag.Bool("debug", false, "turns on debug logging")
+	reconcile = flag.Int("reconcile", 0, "interval in seconds between reconciliation runs (0 = disabled)")
+	liveness = flag.Int("liveness", 0, "interval in seconds between liveness checks of running instances (0 = disabled)")
+	watchdog = flag.Int("watchdog", 0, "interval in seconds between checks for stalled tasks (0 = disabled)")
+	stallPeriod = flag.Int("stall-period", 300, "period in seconds without events after which a task tree is regarded as stalled")
+	repairStalls = flag.Bool("repair-stalls", false, "re-emits the missing events of stalled tasks")
+	modelFile = flag.String("model", "", "file from which the model is loaded at startup")
+	journalFile = flag.String("journal", "", "file in which events and tasks are journaled")
+	workers = flag.Int("workers", 32, "number of workers executing tasks")
+	domainWorkers = flag.Int("domain-workers", 0, "maximum number of tasks executed concurrently per domain (0 = unlimited)")
+	controllerWorkers = flag.Int("controller-workers", 0, "maximum number of tasks executed concurrently per controller type (0 = unlimited)")
+	lockMode = flag.String("lock-mode", "queue", "behaviour of tasks addressing a locked instance (queue/fail)")
 
 	flag.Parse()
+
+	// reject unknown lock modes
+	if *lockMode != "queue" && *lockMode != "fail" {
+		fmt.Fprintf(os.Stderr, "invalid value %q for flag -lock-mode: expected queue or fail\n", *lockMode)
+		flag.Usage()
+		os.Exit(2)
+	}
 }
 
 //------------------------------------------------------------------------------
@@ -23,3 +54,113 @@ func Debug() bool {
 }
 
 //------------------------------------------------------------------------------
+
+// ReconcileInterval provides the interval in seconds between reconciliation runs
+func ReconcileInterval() int {
+	if reconcile == nil {
+		return 0
+	}
+	return *reconcile
+}
+
+//------------------------------------------------------------------------------
+
+// LivenessInterval provides the interval in seconds between liveness checks
+func LivenessInterval() int {
+	if liveness == nil {
+		return 0
+	}
+	return *liveness
+}
+
+//------------------------------------------------------------------------------
+
+// WatchdogInterval provides the interval in seconds between checks for stalled tasks
+func WatchdogInterval() int {
+	if watchdog == nil {
+		return 0
+	}
+	return *watchdog
+}
+
+//------------------------------------------------------------------------------
+
+// StallPeriod provides the period in seconds without events after which a task tree is regarded as stalled
+func StallPeriod() int {
+	if stallPeriod == nil {
+		return 300
+	}
+	return *stallPeriod
+}
+
+//------------------------------------------------------------------------------
+
+// RepairStalls indicates if the missing events of stalled tasks are to be re-emitted
+func RepairStalls() bool {
+	if repairStalls == nil {
+		return false
+	}
+	return *repairStalls
+}
+
+//------------------------------------------------------------------------------
+
+// ModelFile provides the name of the file from which the model is loaded at startup
+func ModelFile() string {
+	if modelFile == nil {
+		return ""
+	}
+	return *modelFile
+}
+
+//------------------------------------------------------------------------------
+
+// JournalFile provides the name of the file in which events and tasks are journaled
+func JournalFile() string {
+	if journalFile == nil {
+		return ""
+	}
+	return *journalFile
+}
+
+//------------------------------------------------------------------------------
+
+// Workers provides the number of workers executing tasks
+func Workers() int {
+	if workers == nil {
+		return 32
+	}
+	return *workers
+}
+
+//------------------------------------------------------------------------------
+
+// DomainWorkers provides the maximum number of tasks executed concurrently per domain
+func DomainWorkers() int {
+	if domainWorkers == nil {
+		return 0
+	}
+	return *domainWorkers
+}
+
+//------------------------------------------------------------------------------
+
+// ControllerWorkers provides the maximum number of tasks executed concurrently per controller type
+func ControllerWorkers() int {
+	if controllerWorkers == nil {
+		return 0
+	}
+	return *controllerWorkers
+}
+
+//------------------------------------------------------------------------------
+
+// LockMode provides the behaviour of tasks addressing a locked instance
+func LockMode() string {
+	if lockMode == nil {
+		return "queue"
+	}
+	return *lockMode
+}
+
+//------------------------------------------------------------------------------